@@ -14,11 +14,15 @@ import (
 	obsidianutils "github.com/sascha-andres/obsidian-utils"
 	"github.com/sascha-andres/obsidian-utils/internal"
 	"github.com/sascha-andres/obsidian-utils/internal/meeting"
+	"github.com/sascha-andres/obsidian-utils/internal/recurrence"
 )
 
 var (
 	folder, interval, meetingFolder, dateTime, title, logLevel string
-	recurring, noDatePrefix, printConfig, dryRun               bool
+	templateFile, timezone, rrule, days, until, holidayFile    string
+	dailyFolder, dailyHeadline                                 string
+	recurring, noDatePrefix, printConfig, dryRun, preview      bool
+	linkDailyNote, linkSeries, carryOver                       bool
 	times                                                      int
 )
 
@@ -31,12 +35,24 @@ func init() {
 	flag.StringVar(&meetingFolder, "meeting-folder", "", "where to store the meeting notes")
 	flag.BoolVar(&noDatePrefix, "no-date-prefix", false, "pass to not add yyyy-mm-dd prefix to filename")
 	flag.BoolVar(&recurring, "recurring", false, "pass to create recurring meeting notes")
-	flag.StringVar(&interval, "interval", "daily", "pass interval size (daily/weekly/bi-weekly)")
+	flag.StringVar(&interval, "interval", "daily", "pass interval size (daily/weekdays/weekly/bi-weekly/monthly/monthly-weekday/yearly)")
+	flag.StringVar(&days, "days", "", "comma separated list of weekdays for recurring meetings, e.g. MO,TH")
+	flag.StringVar(&rrule, "rrule", "", "recurrence rule (RFC 5545), e.g. FREQ=MONTHLY;BYDAY=-1FR, overrides -interval and -days")
 	flag.IntVar(&times, "times", 1, "pass number of times to create meeting notes")
+	flag.StringVar(&until, "until", "", "last date for recurring meetings (2006-01-02)")
+	flag.StringVar(&holidayFile, "holidays", "", "path to file listing dates (2006-01-02) to skip for recurring meetings")
+	flag.BoolVar(&linkDailyNote, "link-daily", false, "pass to link meeting notes in the daily note of their day")
+	flag.StringVar(&dailyFolder, "daily-folder", "", "where the daily notes are stored inside the vault")
+	flag.StringVar(&dailyHeadline, "daily-headline", meeting.DefaultDailyHeadline, "headline in the daily note to link meetings below")
+	flag.BoolVar(&linkSeries, "link-series", false, "pass to link the meeting notes of a series with each other and a series index note")
+	flag.BoolVar(&carryOver, "carry-over", false, "pass to copy open action items of the previous meeting of the series, requires -link-series")
+	flag.BoolVar(&preview, "preview", false, "pass to list the dates of the meetings without creating notes")
 	flag.BoolVar(&printConfig, "print-config", false, "print configuration")
 	flag.BoolVar(&dryRun, "dry-run", false, "pass to not create files")
 	flag.StringVar(&dateTime, "date-time", "", "pass date and time in format yyyy-mm-dd hh:mm")
 	flag.StringVar(&title, "title", "", "pass title")
+	flag.StringVar(&templateFile, "template-file", "", "path to template file for meeting notes")
+	flag.StringVar(&timezone, "timezone", "", "timezone of the appointment, e.g. Europe/Berlin (default: local)")
 }
 
 // main is the entry point of the program.
@@ -82,22 +98,44 @@ func run(logger *slog.Logger) error {
 		return errors.New("-meeting-folder must be non empty")
 	}
 
+	if linkDailyNote && dailyFolder == "" {
+		return errors.New("-daily-folder must be non empty to link meetings in daily notes")
+	}
+	if carryOver && !linkSeries {
+		return errors.New("-carry-over requires -link-series")
+	}
+	if linkSeries && noDatePrefix {
+		return errors.New("-link-series requires the date prefix, do not pass -no-date-prefix")
+	}
+	var daily *meeting.DailyLinks
+	if linkDailyNote {
+		daily = &meeting.DailyLinks{Folder: path.Join(folder, dailyFolder), Headline: dailyHeadline, DryRun: dryRun, Logger: logger}
+	}
 	folder = path.Join(folder, meetingFolder)
+	loc, err := internal.LoadLocation(timezone)
+	if err != nil {
+		return err
+	}
 
+	if rrule != "" || until != "" || days != "" {
+		// these flags only make sense for recurring meetings
+		recurring = true
+	}
 	if recurring {
-		if interval != "daily" && interval != "weekly" && interval != "bi-weekly" {
-			return errors.New("invalid interval")
-		}
 		if times < 1 {
 			return errors.New("invalid times")
 		}
-		if times == 1 {
+		if times == 1 && until == "" && rrule == "" && days == "" {
 			recurring = false
 		}
 	} else {
 		times = 1
 		interval = "daily"
 	}
+	holidays, err := recurrence.ReadHolidays(holidayFile)
+	if err != nil {
+		return err
+	}
 
 	if printConfig {
 		fmt.Printf("meeting notes folder: %q\n", folder)
@@ -105,10 +143,17 @@ func run(logger *slog.Logger) error {
 		fmt.Printf("recurring: %t\n", recurring)
 		fmt.Printf("noDatePrefix: %t\n", noDatePrefix)
 		fmt.Printf("times: %d\n", times)
+		fmt.Printf("until: %q\n", until)
+		fmt.Printf("rrule: %q\n", rrule)
+		fmt.Printf("holidays: %d\n", len(holidays))
+		fmt.Printf("link daily: %t (%q below %q)\n", linkDailyNote, dailyFolder, dailyHeadline)
+		fmt.Printf("link series: %t (carry over: %t)\n", linkSeries, carryOver)
+		fmt.Printf("template file: %q\n", templateFile)
+		fmt.Printf("timezone: %q\n", loc)
 		return nil
 	}
 
-	if !dryRun {
+	if !dryRun && !preview {
 		err = os.MkdirAll(folder, 0700)
 		if err != nil {
 			if !os.IsExist(err) {
@@ -119,14 +164,14 @@ func run(logger *slog.Logger) error {
 
 	var ts string
 	if dateTime != "" {
-		_, err = time.Parse("2006-01-02 15:04", dateTime)
+		_, err = parseDateTime(dateTime, loc)
 		if err != nil {
 			return err
 		}
 		ts = dateTime
 	} else {
-		ts, err = internal.PromptText("provide date and time (2006-01-02 15:04)", time.Now().Format("2006-01-02 15:04"), func(i string) error {
-			_, err := time.Parse("2006-01-02 15:04", i)
+		ts, err = internal.PromptText("provide date and time (2006-01-02 15:04)", time.Now().In(loc).Format("2006-01-02 15:04"), func(i string) error {
+			_, err := parseDateTime(i, loc)
 			return err
 		})
 		if err != nil {
@@ -134,6 +179,32 @@ func run(logger *slog.Logger) error {
 		}
 	}
 
+	t, err := parseDateTime(ts, loc)
+	if err != nil {
+		return err
+	}
+	dates := []time.Time{t}
+	var rule string
+	if recurring {
+		options := recurrence.Options{Rule: rrule, Interval: interval, Days: days, Times: times, Until: until}
+		if rule, err = options.Build(t); err != nil {
+			return err
+		}
+		if dates, err = recurrence.Occurrences(rule, t, holidays); err != nil {
+			return err
+		}
+	}
+
+	if preview {
+		for _, d := range dates {
+			fmt.Printf("%s %s\n", d.Format("Mon 2006-01-02 15:04"), d.Format("-07:00"))
+		}
+		if rule != "" {
+			fmt.Printf("%d meetings (%s)\n", len(dates), rule)
+		}
+		return nil
+	}
+
 	var localTitle string
 	if title != "" {
 		localTitle = title
@@ -149,34 +220,55 @@ func run(logger *slog.Logger) error {
 		}
 	}
 
-	t, err := time.Parse("2006-01-02 15:04", ts)
-	if err != nil {
-		return err
-	}
-
-	for i := 0; i < times; i++ {
-		if i > 0 {
-			if interval == "daily" {
-				t = t.Add(time.Hour * 24)
-			}
-			if interval == "weekly" {
-				t = t.Add(time.Hour * 168)
-			}
-			if interval == "bi-weekly" {
-				t = t.Add(time.Hour * 336)
-			}
-		}
-		logger.Info("trying to create meeting", "title", localTitle, "appointment", t)
-
+	fileNames := make([]string, 0, len(dates))
+	existing := make([]bool, 0, len(dates))
+	for _, t := range dates {
 		fullName, err := obsidianutils.CreateFileName(folder, localTitle, noDatePrefix, t)
 		if err != nil {
 			return err
 		}
-		if dryRun {
+		exists, err := internal.Exists(fullName)
+		if err != nil {
+			return err
+		}
+		fileNames = append(fileNames, fullName)
+		existing = append(existing, exists)
+	}
+	var s *series
+	if linkSeries {
+		if s, err = loadSeries(folder, localTitle, rule); err != nil {
+			return err
+		}
+		for i, fullName := range fileNames {
+			s.add(fullName, !existing[i])
+		}
+	}
+
+	for i, t := range dates {
+		logger.Info("trying to create meeting", "title", localTitle, "appointment", t)
+
+		fullName := fileNames[i]
+		if s != nil && existing[i] {
+			// the note belongs to the series already, it is linked but its content is kept
+			logger.Info("skipping existing meeting note", "file", fullName)
+		} else if dryRun {
 			fmt.Printf("would create meeting with [%s] on [%s] in [%s]\n", localTitle, t, fullName)
 		} else {
 			fmt.Printf("creating meeting with [%s] on [%s] in [%s]\n", localTitle, t, fullName)
-			m, err := meeting.NewMeeting(meeting.WithTitle(localTitle))
+			opts := []meeting.OptionFunc{meeting.WithTitle(localTitle), meeting.WithTemplate(templateFile)}
+			if rule != "" {
+				opts = append(opts, meeting.WithRecurrence(rule))
+			}
+			var carriedFrom string
+			if s != nil {
+				seriesOpts, from, err := s.options(logger, fullName, carryOver)
+				if err != nil {
+					return err
+				}
+				opts = append(opts, seriesOpts...)
+				carriedFrom = from
+			}
+			m, err := meeting.NewMeeting(opts...)
 			if err != nil {
 				return err
 			}
@@ -188,8 +280,41 @@ func run(logger *slog.Logger) error {
 			if err = os.WriteFile(fullName, []byte(c), 0600); err != nil {
 				return err
 			}
+			if carriedFrom != "" {
+				if err := s.markCarriedOver(logger, carriedFrom, time.Now().In(loc)); err != nil {
+					return err
+				}
+			}
+		}
+		if err := daily.Link(fullName, t); err != nil {
+			return err
 		}
 	}
 
+	if s != nil {
+		return s.save(logger)
+	}
 	return nil
 }
+
+// dateTimeLayouts are the accepted layouts for the appointment, the first one without timezone.
+var dateTimeLayouts = []string{"2006-01-02 15:04", "2006-01-02 15:04 -07:00", "2006-01-02 15:04 -0700", time.RFC3339}
+
+// parseDateTime parses the appointment. Input without timezone is read in loc, the result is always returned in
+// loc so dates and the daily note link match the local calendar.
+func parseDateTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	var err error
+	for i, layout := range dateTimeLayouts {
+		var t time.Time
+		if i == 0 {
+			t, err = time.ParseInLocation(layout, value, loc)
+		} else {
+			t, err = time.Parse(layout, value)
+		}
+		if err == nil {
+			return t.In(loc), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date and time %q, expected 2006-01-02 15:04: %w", value, err)
+}
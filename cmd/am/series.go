@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"time"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
+	"github.com/sascha-andres/obsidian-utils/internal/meeting"
+)
+
+// series links the notes of a recurring meeting with each other and with the series index note.
+type series struct {
+	// folder contains the meeting notes and the index.
+	folder string
+
+	// index is the file of the series index note.
+	index string
+
+	// data is the content of the index, a new index if the file does not exist yet.
+	data []byte
+
+	// notes are the names of all notes of the series, sorted by name.
+	notes []string
+
+	// added are the names of the notes created by this run.
+	added map[string]bool
+}
+
+// loadSeries reads the series index of the meeting title from the meeting folder. A missing index is created when
+// saving the series.
+func loadSeries(folder, title, rule string) (*series, error) {
+	index, err := obsidianutils.CreateFileName(folder, title+" Series", true, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	s := &series{folder: folder, index: index, added: make(map[string]bool)}
+	s.data, err = os.ReadFile(index)
+	if errors.Is(err, os.ErrNotExist) {
+		s.data = []byte(meeting.SeriesIndexContent(title, rule))
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.notes = meeting.SeriesNotes(s.data)
+	slices.Sort(s.notes)
+	return s, nil
+}
+
+// add adds a note file of this run to the series. created tells whether the note is created by this run, existing
+// notes are kept and only linked.
+func (s *series) add(noteFile string, created bool) {
+	name := meeting.NoteName(noteFile)
+	if created {
+		s.added[name] = true
+	}
+	if !slices.Contains(s.notes, name) {
+		s.notes = append(s.notes, name)
+		slices.Sort(s.notes)
+	}
+}
+
+// neighbours returns the names of the notes before and after the note in the series.
+func (s *series) neighbours(name string) (previous, next string) {
+	i := slices.Index(s.notes, name)
+	if i > 0 {
+		previous = s.notes[i-1]
+	}
+	if i >= 0 && i < len(s.notes)-1 {
+		next = s.notes[i+1]
+	}
+	return previous, next
+}
+
+// options returns the meeting options linking the note file to its neighbours and the index. With carryOver the
+// open action items of the previous note are added, unless that note is created by this run as well. The file the
+// action items are carried over from is returned, empty if there are none, see markCarriedOver.
+func (s *series) options(logger *slog.Logger, noteFile string, carryOver bool) ([]meeting.OptionFunc, string, error) {
+	previous, next := s.neighbours(meeting.NoteName(noteFile))
+	opts := []meeting.OptionFunc{meeting.WithSeries(meeting.NoteName(s.index), previous, next)}
+	if !carryOver || previous == "" || s.added[previous] {
+		return opts, "", nil
+	}
+	previousFile := path.Join(s.folder, previous+".md")
+	data, err := os.ReadFile(previousFile)
+	if errors.Is(err, os.ErrNotExist) {
+		logger.Warn("previous meeting note does not exist, no action items carried over", "file", previousFile)
+		return opts, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	items := meeting.OpenActionItems(data)
+	if len(items) == 0 {
+		return opts, "", nil
+	}
+	logger.Info("carrying over open action items", "from", previousFile, "items", len(items))
+	return append(opts, meeting.WithActionItems(items...)), previousFile, nil
+}
+
+// markCarriedOver cancels the open action items of the note file on date once they are written to the next
+// occurrence, so they are listed only once by task queries.
+func (s *series) markCarriedOver(logger *slog.Logger, noteFile string, date time.Time) error {
+	data, err := os.ReadFile(noteFile)
+	if err != nil {
+		return err
+	}
+	data, changed := meeting.MarkCarriedOver(data, date)
+	if !changed {
+		return nil
+	}
+	logger.Info("marked carried over action items as cancelled", "file", noteFile)
+	return os.WriteFile(noteFile, data, 0600)
+}
+
+// save links the notes of this run in the index and updates the navigation of the existing notes next to the notes
+// created.
+func (s *series) save(logger *slog.Logger) error {
+	var err error
+	for _, name := range s.notes {
+		if s.data, _, err = meeting.AddToSeriesIndex(s.data, name+".md"); err != nil {
+			return err
+		}
+	}
+	if dryRun {
+		fmt.Printf("would update series index [%s]\n", s.index)
+	} else if err = os.WriteFile(s.index, s.data, 0600); err != nil {
+		return err
+	}
+
+	for i, name := range s.notes {
+		if s.added[name] {
+			continue
+		}
+		if (i == 0 || !s.added[s.notes[i-1]]) && (i == len(s.notes)-1 || !s.added[s.notes[i+1]]) {
+			continue
+		}
+		if err := s.updateNavigation(logger, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateNavigation rewrites the navigation line of an existing note of the series. Missing notes and notes without
+// navigation line are skipped.
+func (s *series) updateNavigation(logger *slog.Logger, name string) error {
+	noteFile := path.Join(s.folder, name+".md")
+	data, err := os.ReadFile(noteFile)
+	if errors.Is(err, os.ErrNotExist) {
+		logger.Warn("meeting note of series does not exist", "file", noteFile)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	previous, next := s.neighbours(name)
+	data, changed := meeting.UpdateSeriesNavigation(data, previous, meeting.NoteName(s.index), next)
+	if !changed {
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("would update links in [%s]\n", noteFile)
+		return nil
+	}
+	logger.Info("updated links of meeting note", "file", noteFile)
+	return os.WriteFile(noteFile, data, 0600)
+}
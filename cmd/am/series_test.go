@@ -0,0 +1,175 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sascha-andres/obsidian-utils/internal/meeting"
+)
+
+// writeSeriesFolder writes the index of the series W listing the existing note 2026-11-03 W, which has an open action
+// item, and returns the folder.
+func writeSeriesFolder(t *testing.T) string {
+	t.Helper()
+	folder := t.TempDir()
+	files := map[string]string{
+		"W Series.md":     meeting.SeriesIndexContent("W", "") + "\n- [[2026-11-03 W]]\n",
+		"2026-11-03 W.md": "# W\n\n<< [[W Series|Series]] >>\n\n## Action items\n\n- [ ] Send slides\n- [x] Book room\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(folder, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return folder
+}
+
+func TestSeriesNeighbours(t *testing.T) {
+	s := &series{notes: []string{"2026-11-03 W", "2026-11-10 W", "2026-11-17 W"}}
+	tests := []struct {
+		name         string
+		note         string
+		wantPrevious string
+		wantNext     string
+	}{
+		{name: "First", note: "2026-11-03 W", wantNext: "2026-11-10 W"},
+		{name: "Middle", note: "2026-11-10 W", wantPrevious: "2026-11-03 W", wantNext: "2026-11-17 W"},
+		{name: "Last", note: "2026-11-17 W", wantPrevious: "2026-11-10 W"},
+		{name: "Unknown", note: "2026-11-24 W"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous, next := s.neighbours(tt.note)
+			if previous != tt.wantPrevious || next != tt.wantNext {
+				t.Errorf("neighbours() = %q, %q, want %q, %q", previous, next, tt.wantPrevious, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestSeriesOptions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tests := []struct {
+		name            string
+		existing        []string
+		created         []string
+		note            string
+		carryOver       bool
+		wantCarriedFrom string
+		wantContains    []string
+	}{
+		{
+			name:            "Carry over from an existing note",
+			existing:        []string{"2026-11-03 W.md"},
+			created:         []string{"2026-11-10 W.md"},
+			note:            "2026-11-10 W.md",
+			carryOver:       true,
+			wantCarriedFrom: "2026-11-03 W.md",
+			wantContains:    []string{"[[2026-11-03 W|Previous]] | [[W Series|Series]] >>", "- [ ] Send slides"},
+		},
+		{
+			name:         "No carry over without flag",
+			existing:     []string{"2026-11-03 W.md"},
+			created:      []string{"2026-11-10 W.md"},
+			note:         "2026-11-10 W.md",
+			wantContains: []string{"[[2026-11-03 W|Previous]]"},
+		},
+		{
+			name:         "No carry over from a note created by the same run",
+			created:      []string{"2026-11-10 W.md", "2026-11-17 W.md"},
+			note:         "2026-11-17 W.md",
+			carryOver:    true,
+			wantContains: []string{"[[2026-11-10 W|Previous]]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folder := writeSeriesFolder(t)
+			s, err := loadSeries(folder, "W", "")
+			if err != nil {
+				t.Fatalf("loadSeries() error = %v", err)
+			}
+			for _, f := range tt.existing {
+				s.add(filepath.Join(folder, f), false)
+			}
+			for _, f := range tt.created {
+				s.add(filepath.Join(folder, f), true)
+			}
+			opts, carriedFrom, err := s.options(logger, filepath.Join(folder, tt.note), tt.carryOver)
+			if err != nil {
+				t.Fatalf("options() error = %v", err)
+			}
+			wantCarriedFrom := ""
+			if tt.wantCarriedFrom != "" {
+				wantCarriedFrom = filepath.Join(folder, tt.wantCarriedFrom)
+			}
+			if carriedFrom != wantCarriedFrom {
+				t.Errorf("options() carried from %q, want %q", carriedFrom, wantCarriedFrom)
+			}
+			m, err := meeting.NewMeeting(append(opts, meeting.WithTitle("W"))...)
+			if err != nil {
+				t.Fatalf("NewMeeting() error = %v", err)
+			}
+			content, err := m.CreateContent("W", time.Date(2026, 11, 10, 10, 0, 0, 0, time.UTC))
+			if err != nil {
+				t.Fatalf("CreateContent() error = %v", err)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(content, want) {
+					t.Errorf("options() content = %q, want it to contain %q", content, want)
+				}
+			}
+			if !tt.carryOver && strings.Contains(content, "Send slides") {
+				t.Errorf("options() content = %q, want no action items", content)
+			}
+		})
+	}
+}
+
+func TestSeriesSave(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	folder := writeSeriesFolder(t)
+	s, err := loadSeries(folder, "W", "")
+	if err != nil {
+		t.Fatalf("loadSeries() error = %v", err)
+	}
+	existing := filepath.Join(folder, "2026-11-03 W.md")
+	s.add(existing, false)
+	s.add(filepath.Join(folder, "2026-11-10 W.md"), true)
+	if err := s.markCarriedOver(logger, existing, time.Date(2026, 11, 10, 10, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("markCarriedOver() error = %v", err)
+	}
+	if err := s.save(logger); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{file: "W Series.md", want: []string{"- [[2026-11-03 W]]\n- [[2026-11-10 W]]\n"}},
+		{file: "2026-11-03 W.md", want: []string{
+			"<< [[W Series|Series]] | [[2026-11-10 W|Next]] >>\n",
+			"- [-] Send slides ❌ 2026-11-10\n",
+			"- [x] Book room\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(folder, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("save() wrote %q, want it to contain %q", data, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sascha-andres/reuse/flag"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
+	"github.com/sascha-andres/obsidian-utils/internal"
+	"github.com/sascha-andres/obsidian-utils/internal/journal"
+	"github.com/sascha-andres/obsidian-utils/internal/markdown"
+	"github.com/sascha-andres/obsidian-utils/internal/tasks"
+)
+
+var (
+	folder, dailyFolder, forDate, rulesFile, logLevel string
+	timezone, at                                      string
+	printConfig, dryRun                               bool
+)
+
+// init initializes the package by setting up flag options, log flags, and prefix.
+func init() {
+	internal.AddCommonFlagPrefixes()
+	flag.SetEnvPrefix("OBS_UTIL_CAPTURE")
+	flag.StringVar(&logLevel, "log-level", "info", "pass log level (debug/info/warn/error)")
+	flag.StringVar(&folder, "folder", "", "base path to obsidian vault")
+	flag.StringVar(&dailyFolder, "daily-folder", "", "where the daily notes are stored inside the vault")
+	flag.StringVar(&forDate, "for-date", "", "date of the daily note to capture to (2006-01-02 or +-offset)")
+	flag.StringVar(&rulesFile, "rules-file", "", "path to routing rules (default: capture.yaml in the user config directory)")
+	flag.StringVar(&timezone, "timezone", "", "timezone for the entry time, e.g. Europe/Berlin (default: local)")
+	flag.StringVar(&at, "at", "", "time of the entries (15:04), required for a time prefix when not capturing to today's note")
+	flag.BoolVar(&printConfig, "print-config", false, "print configuration")
+	flag.BoolVar(&dryRun, "dry-run", false, "pass to print the changes instead of editing notes")
+}
+
+// main is the entry point of the program.
+func main() {
+	flag.Parse()
+	internal.PrintFlags()
+	logger := internal.CreateLogger(logLevel, "OBS_UTIL_CAPTURE")
+	if err := run(logger); err != nil {
+		logger.Error("error running capture", "err", err)
+		os.Exit(1)
+	}
+}
+
+// run captures the text passed as arguments, or each line read from stdin if there are none, and routes it to
+// the note and headline selected by the rules.
+func run(logger *slog.Logger) error {
+	if folder == "" {
+		return errors.New("-folder must be non empty")
+	}
+	vault, err := obsidianutils.ApplyDirectoryPlaceHolder(folder)
+	if err != nil {
+		return err
+	}
+	if dailyFolder == "" {
+		return errors.New("-daily-folder must be non empty")
+	}
+	loc, err := internal.LoadLocation(timezone)
+	if err != nil {
+		return err
+	}
+	day, err := internal.ResolveDate(forDate, time.Now().In(loc))
+	if err != nil {
+		return err
+	}
+
+	explicit := rulesFile != ""
+	if !explicit {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return err
+		}
+		rulesFile = filepath.Join(configDir, "obsidian-utils", "capture.yaml")
+	}
+	cfg, err := loadConfig(rulesFile, explicit)
+	if err != nil {
+		return err
+	}
+
+	if printConfig {
+		fmt.Printf("rules file: %q\n", rulesFile)
+		fmt.Printf("daily note: %q\n", internal.DailyNotePath(path.Join(vault, dailyFolder), day))
+		for _, r := range cfg.Rules {
+			fmt.Printf("rule %q: note=%q headline=%q task=%t\n", r.Prefix, r.Note, r.headline(), r.Task)
+		}
+		return nil
+	}
+
+	entries := flag.GetVerbs()
+	if len(entries) > 0 {
+		entries = []string{strings.Join(entries, " ")}
+	} else {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if strings.TrimSpace(scanner.Text()) != "" {
+				entries = append(entries, scanner.Text())
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	if len(entries) == 0 {
+		return errors.New("nothing to capture")
+	}
+
+	for _, entry := range entries {
+		if err := capture(logger, cfg, vault, day, loc, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// capture routes a single entry and adds it to the target note.
+func capture(logger *slog.Logger, cfg Config, vault string, day time.Time, loc *time.Location, entry string) error {
+	rule, text := cfg.route(entry)
+	if text == "" {
+		return fmt.Errorf("empty entry after removing prefix %q", rule.Prefix)
+	}
+	target := internal.DailyNotePath(path.Join(vault, dailyFolder), day)
+	if rule.Note != "" {
+		target = path.Join(vault, rule.Note)
+	}
+
+	line, before, err := cfg.createLine(rule, text, day, loc, time.Now())
+	if err != nil {
+		return err
+	}
+
+	fileData, err := os.ReadFile(target)
+	if err != nil {
+		return fmt.Errorf("could not read target note %s: %w", target, err)
+	}
+	newFileData, err := markdown.AddBulletpoint(fileData, line, rule.headline(), before)
+	if err != nil {
+		logger.Error("could not add bullet point", "err", err, "file", target, "headline", rule.headline())
+		return err
+	}
+
+	if dryRun {
+		fmt.Println(cmp.Diff(string(fileData), string(newFileData)))
+		return nil
+	}
+	if err := os.WriteFile(target, newFileData, 0640); err != nil {
+		return err
+	}
+	logger.Info("captured entry", "file", target, "headline", rule.headline(), "entry", line)
+	return nil
+}
+
+// createLine renders the entry as task or as journal bullet. Journal bullets are prefixed using the entry prefix
+// template like jrnl does and are kept in time order. The entry time is -at or now if capturing to today's note.
+func (cfg Config) createLine(rule Rule, text string, day time.Time, loc *time.Location, now time.Time) (string, func(string) bool, error) {
+	if rule.Task {
+		format, err := tasks.ParseFormat(cfg.TaskFormat)
+		if err != nil {
+			return "", nil, err
+		}
+		t := tasks.Task{Status: tasks.StatusOpen, Description: text}
+		for _, tag := range rule.Tags {
+			t.Tags = append(t.Tags, tasks.NormalizeTag(tag, cfg.TagPrefix))
+		}
+		line, err := t.Render(format)
+		return line, nil, err
+	}
+	entryTime, ok, err := journal.ResolveTime(day, at, loc, now)
+	if err != nil {
+		return "", nil, err
+	}
+	ok = ok && !rule.NoTime
+	prefix, err := journal.RenderPrefix(cfg.EntryPrefix, cfg.TimeFormat, entryTime, ok)
+	if err != nil {
+		return "", nil, err
+	}
+	if !ok {
+		return prefix + text, nil, nil
+	}
+	return prefix + text, markdown.LaterThan(entryTime), nil
+}
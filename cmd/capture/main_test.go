@@ -0,0 +1,111 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sascha-andres/obsidian-utils/internal/journal"
+)
+
+func TestCreateLine(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{EntryPrefix: journal.DefaultPrefix, TimeFormat: "15:04", TaskFormat: "emoji", TagPrefix: "task/"}
+	now := time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC)
+	today := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	yesterday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		cfg         Config
+		rule        Rule
+		day         time.Time
+		at          string
+		want        string
+		wantOrdered bool
+		wantErr     bool
+	}{
+		{
+			name:        "Today in the timezone",
+			cfg:         cfg,
+			day:         today,
+			want:        "(01:30) went for a walk",
+			wantOrdered: true,
+		},
+		{
+			name: "Other day without time",
+			cfg:  cfg,
+			day:  yesterday,
+			want: "went for a walk",
+		},
+		{
+			name:        "Other day with -at",
+			cfg:         cfg,
+			day:         yesterday,
+			at:          "18:30",
+			want:        "(18:30) went for a walk",
+			wantOrdered: true,
+		},
+		{
+			name: "Rule without time",
+			cfg:  cfg,
+			rule: Rule{NoTime: true},
+			day:  today,
+			want: "went for a walk",
+		},
+		{
+			name:        "Prefix template",
+			cfg:         Config{EntryPrefix: "{{ .Weekday }} {{ .Time }}: ", TimeFormat: "15:04:05"},
+			day:         today,
+			want:        "Tuesday 01:30:00: went for a walk",
+			wantOrdered: true,
+		},
+		{
+			name: "Prefix template without time",
+			cfg:  Config{EntryPrefix: "{{ .Date }}{{ with .Time }} {{ . }}{{ end }}: ", TimeFormat: "15:04"},
+			day:  yesterday,
+			want: "2026-10-19: went for a walk",
+		},
+		{
+			name: "Empty prefix",
+			cfg:  Config{TimeFormat: "15:04"},
+			day:  today,
+			want: "went for a walk",
+			// the entry time is still known, so the entry is placed in time order
+			wantOrdered: true,
+		},
+		{
+			name: "Task",
+			cfg:  cfg,
+			rule: Rule{Task: true, Tags: []string{"todo", "#home"}},
+			day:  today,
+			want: "[ ] went for a walk #task/todo #home",
+		},
+		{
+			name:    "Invalid -at",
+			cfg:     cfg,
+			day:     today,
+			at:      "6pm",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := at
+			t.Cleanup(func() { at = saved })
+			at = tt.at
+			got, before, err := tt.cfg.createLine(tt.rule, "went for a walk", tt.day, berlin, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("createLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("createLine() = %q, want %q", got, tt.want)
+			}
+			if (before != nil) != tt.wantOrdered {
+				t.Errorf("createLine() ordered = %t, want %t", before != nil, tt.wantOrdered)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/sascha-andres/obsidian-utils/internal/journal"
+)
+
+type (
+
+	// Rule routes captured text to a headline of a note.
+	Rule struct {
+		// Prefix selects the rule if captured text starts with it, e.g. "w:". The prefix is removed from the text.
+		Prefix string `yaml:"prefix"`
+
+		// Note is the path of the target note relative to the vault. The daily note is used if empty.
+		Note string `yaml:"note"`
+
+		// Headline is the headline below which the entry is placed. A path like "Health > Food" is supported.
+		Headline string `yaml:"headline"`
+
+		// Task adds the entry as task instead of a journal bullet.
+		Task bool `yaml:"task"`
+
+		// Tags are added to tasks, tags not starting with '#' are prefixed with the tag prefix.
+		Tags []string `yaml:"tags"`
+
+		// NoTime disables the time prefix for journal bullets.
+		NoTime bool `yaml:"no-time"`
+	}
+
+	// Config contains the routing rules and settings of the capture command.
+	Config struct {
+		// Default is used for text not matching any rule.
+		Default Rule `yaml:"default"`
+
+		// Rules are evaluated in order, the first rule with a matching prefix wins.
+		Rules []Rule `yaml:"rules"`
+
+		// EntryPrefix is the template for the prefix of journal bullets, see jrnl -entry-prefix. Empty for none.
+		EntryPrefix string `yaml:"entry-prefix"`
+
+		// TimeFormat is the go time layout used for .Time in the entry prefix, it has to contain 15:04.
+		TimeFormat string `yaml:"time-format"`
+
+		// TaskFormat selects the syntax for task metadata (emoji/dataview).
+		TaskFormat string `yaml:"task-format"`
+
+		// TagPrefix is prepended to task tags not starting with '#'.
+		TagPrefix string `yaml:"tag-prefix"`
+	}
+)
+
+const (
+	// defaultHeadline is used for journal bullets if the rule has no headline.
+	defaultHeadline = "## Other stuff"
+
+	// defaultTasksHeadline is used for tasks if the rule has no headline.
+	defaultTasksHeadline = "## Tasks"
+)
+
+// loadConfig reads the rules file. A missing file is only an error if it was passed explicitly, otherwise the
+// built-in defaults are used, which put everything below the default headline of the daily note.
+func loadConfig(fileName string, explicit bool) (Config, error) {
+	// the entry prefix is set before reading the file, so an empty entry-prefix disables it
+	cfg := Config{EntryPrefix: journal.DefaultPrefix}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) || explicit {
+			return cfg, err
+		}
+	} else if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("could not parse rules file %s: %w", fileName, err)
+	}
+	if cfg.TimeFormat == "" {
+		cfg.TimeFormat = "15:04"
+	}
+	if err := journal.ValidateTimeFormat(cfg.TimeFormat); err != nil {
+		return cfg, fmt.Errorf("rules file %s: %w", fileName, err)
+	}
+	if cfg.TaskFormat == "" {
+		cfg.TaskFormat = "emoji"
+	}
+	if cfg.TagPrefix == "" {
+		cfg.TagPrefix = "task/"
+	}
+	return cfg, nil
+}
+
+// route returns the rule for the captured text and the text without the rule prefix.
+func (cfg Config) route(text string) (Rule, string) {
+	text = strings.TrimSpace(text)
+	for _, r := range cfg.Rules {
+		if r.Prefix != "" && len(text) >= len(r.Prefix) && strings.EqualFold(text[:len(r.Prefix)], r.Prefix) {
+			return r, strings.TrimSpace(text[len(r.Prefix):])
+		}
+	}
+	return cfg.Default, text
+}
+
+// headline returns the headline to place an entry for the rule below.
+func (r Rule) headline() string {
+	switch {
+	case r.Headline != "":
+		return r.Headline
+	case r.Task:
+		return defaultTasksHeadline
+	}
+	return defaultHeadline
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sascha-andres/obsidian-utils/internal/journal"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		explicit bool
+		want     Config
+		wantErr  bool
+	}{
+		{
+			name: "Rules file",
+			file: "capture.yaml",
+			want: Config{
+				Default: Rule{Headline: "## Inbox"},
+				Rules: []Rule{
+					{Prefix: "w:", Headline: "## Work"},
+					{Prefix: "t:", Task: true, Tags: []string{"todo"}},
+				},
+				EntryPrefix: "{{ .Time }} ",
+				TimeFormat:  "15:04",
+				TaskFormat:  "emoji",
+				TagPrefix:   "task/",
+			},
+		},
+		{
+			name: "Empty entry prefix",
+			file: "no-prefix.yaml",
+			want: Config{TimeFormat: "15:04", TaskFormat: "emoji", TagPrefix: "task/"},
+		},
+		{
+			name: "Missing default file",
+			file: "missing.yaml",
+			want: Config{EntryPrefix: journal.DefaultPrefix, TimeFormat: "15:04", TaskFormat: "emoji", TagPrefix: "task/"},
+		},
+		{
+			name:     "Missing explicit file",
+			file:     "missing.yaml",
+			explicit: true,
+			wantErr:  true,
+		},
+		{
+			name:    "Time format without 15:04",
+			file:    "twelve-hour.yaml",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown key",
+			file:    "invalid.yaml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadConfig(filepath.Join("testdata", tt.file), tt.explicit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("loadConfig() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRoute(t *testing.T) {
+	cfg := Config{
+		Default: Rule{Headline: "## Inbox"},
+		Rules: []Rule{
+			{Prefix: "w:", Headline: "## Work"},
+			{Prefix: "ä:", Headline: "## Umlaut"},
+			{Prefix: "t:", Task: true},
+		},
+	}
+	tests := []struct {
+		name     string
+		text     string
+		wantRule string
+		wantText string
+	}{
+		{name: "Prefix", text: "w: prepare review", wantRule: "## Work", wantText: "prepare review"},
+		{name: "Prefix ignoring case", text: "  W:prepare review ", wantRule: "## Work", wantText: "prepare review"},
+		{name: "First matching rule", text: "t: w: buy milk", wantRule: "## Tasks", wantText: "w: buy milk"},
+		{name: "Multibyte prefix", text: "Ä: umlaut", wantRule: "## Umlaut", wantText: "umlaut"},
+		{name: "Text shorter than prefix", text: "w", wantRule: "## Inbox", wantText: "w"},
+		{name: "No prefix", text: "went for a walk", wantRule: "## Inbox", wantText: "went for a walk"},
+		{name: "Prefix inside text", text: "walk w: later", wantRule: "## Inbox", wantText: "walk w: later"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, text := cfg.route(tt.text)
+			if rule.headline() != tt.wantRule || text != tt.wantText {
+				t.Errorf("route() = %q, %q, want %q, %q", rule.headline(), text, tt.wantRule, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"regexp"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/people/v1"
+
+	"github.com/sascha-andres/obsidian-utils/internal/contacts"
+)
+
+// accountName matches valid names for -account, they are used as directory names.
+var accountName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// validateAccount returns an error if the name can not be used for an account.
+func validateAccount(name string) error {
+	if !accountName.MatchString(name) {
+		return fmt.Errorf("invalid account %q, only letters, digits, - and _ are allowed", name)
+	}
+	return nil
+}
+
+// accountStateDirectory returns the directory storing the token and the sync token of the account selected by
+// -account, the state directory itself if none is selected.
+func accountStateDirectory() string {
+	if account == "" {
+		return stateDirectory
+	}
+	return path.Join(stateDirectory, "accounts", account)
+}
+
+// accountOutputDirectory returns the directory the exports of the account selected by -account are written to.
+func accountOutputDirectory(writeTo string) string {
+	if account == "" {
+		return writeTo
+	}
+	return path.Join(writeTo, account)
+}
+
+// credentialsFile returns the credentials.json of the account, or the one shared by all accounts if it has none.
+func credentialsFile() string {
+	file := path.Join(accountStateDirectory(), "credentials.json")
+	if _, err := os.Stat(file); err == nil {
+		return file
+	}
+	return path.Join(stateDirectory, "credentials.json")
+}
+
+// mergeAccounts reads the exports of the accounts from their output directories below writeTo and merges them.
+func mergeAccounts(logger *slog.Logger, writeTo string, names []string) ([]*people.Person, []*people.ContactGroup, error) {
+	var accounts []contacts.Account
+	for _, name := range names {
+		connections, groups, err := readExport(logger, path.Join(writeTo, name))
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to read the export of account %s: %w", name, err)
+		}
+		accounts = append(accounts, contacts.Account{Name: name, Contacts: connections, Groups: groups})
+	}
+	connections, groups := contacts.Merge(accounts)
+	logger.Info("merged accounts", "accounts", len(accounts), "contacts", len(connections), "groups", len(groups))
+	return connections, groups, nil
+}
+
+// savingTokenSource writes tokens to the token file whenever they were refreshed, so the next run can use them.
+type savingTokenSource struct {
+	logger *slog.Logger
+	file   string
+	base   oauth2.TokenSource
+
+	mu   sync.Mutex
+	last string
+}
+
+// newSavingTokenSource returns a token source refreshing tok using the config and saving refreshed tokens to file.
+func newSavingTokenSource(ctx context.Context, logger *slog.Logger, config *oauth2.Config, tok *oauth2.Token, file string) oauth2.TokenSource {
+	return &savingTokenSource{
+		logger: logger,
+		file:   file,
+		base:   config.TokenSource(ctx, tok),
+		last:   tok.AccessToken,
+	}
+}
+
+// Token returns a valid token, saving it if it was refreshed.
+func (s *savingTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tok.AccessToken == s.last {
+		return tok, nil
+	}
+	if err := saveToken(s.logger, s.file, tok); err != nil {
+		// the token is valid nonetheless, it is refreshed again on the next run
+		s.logger.Error("unable to save refreshed token", "err", err)
+		return tok, nil
+	}
+	s.logger.Debug("saved refreshed token", "file", s.file)
+	s.last = tok.AccessToken
+	return tok, nil
+}
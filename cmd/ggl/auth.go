@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// The ways to authorize ggl, see -auth-flow.
+const (
+	// browserFlow opens the consent page in the browser, which redirects back to a local server.
+	browserFlow = "browser"
+	// manualFlow prints the consent page, the address the browser is redirected to is pasted back. Used when the
+	// browser runs on another machine than ggl, e.g. in an SSH session.
+	manualFlow = "manual"
+)
+
+// callbackPath is the path of the local server the browser is redirected to.
+const callbackPath = "/oauth2callback"
+
+// errStateMismatch is returned for a redirect not belonging to the authorization started by ggl.
+var errStateMismatch = errors.New("state of the redirect does not match, the authorization was not started by ggl")
+
+// callbackResult is the outcome of a redirect to the local server.
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// getTokenFromWeb asks the user to authorize ggl using the flow selected by -auth-flow. The user has to complete the
+// authorization within -auth-timeout.
+func getTokenFromWeb(ctx context.Context, logger *slog.Logger, config *oauth2.Config) (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(ctx, authTimeout)
+	defer cancel()
+
+	var (
+		tok *oauth2.Token
+		err error
+	)
+	switch authFlow {
+	case browserFlow:
+		tok, err = tokenFromLoopback(ctx, logger, config, nil)
+	case manualFlow:
+		tok, err = tokenFromLoopback(ctx, logger, config, os.Stdin)
+	default:
+		return nil, fmt.Errorf("invalid auth flow %q, expected browser or manual", authFlow)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("authorization not completed within %s", authTimeout)
+	}
+	return tok, err
+}
+
+// tokenFromLoopback runs the authorization code flow with PKCE, redirecting to a server on a free port of the loopback
+// interface. A random state is sent along and checked on the redirect. If paste is set the browser is not opened, the
+// address redirected to is read from paste instead, as the server is not reachable from a browser on another machine.
+// A pasted address with a wrong state ends the authorization, as it was pasted by mistake.
+func tokenFromLoopback(ctx context.Context, logger *slog.Logger, config *oauth2.Config, paste io.Reader) (*oauth2.Token, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen for the redirect: %w", err)
+	}
+	config.RedirectURL = fmt.Sprintf("http://%s%s", listener.Addr().String(), callbackPath)
+
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	results := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, callbackHandler(logger, state, results))
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			sendResult(results, callbackResult{err: err})
+		}
+	}()
+	defer func() {
+		if err := server.Shutdown(context.Background()); err != nil {
+			logger.Error("error shutting down server", "err", err)
+		}
+	}()
+
+	authURL := config.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier))
+	fmt.Println("==========================================================")
+	fmt.Println("To authorize this application:")
+	if paste != nil {
+		fmt.Println("1. Open the following URL in a browser:")
+		fmt.Printf("   %v\n", authURL)
+		fmt.Println("2. Sign in and grant access to your Google account")
+		fmt.Println("3. The browser is redirected to a page that does not load,")
+		fmt.Println("   paste its address from the address bar here:")
+	} else {
+		fmt.Println("1. A browser window should open automatically.")
+		fmt.Println("   If it doesn't, please open the following URL:")
+		fmt.Printf("   %v\n", authURL)
+		fmt.Println("2. Sign in and grant access to your Google account")
+	}
+	fmt.Println("==========================================================")
+
+	if paste != nil {
+		// a read from stdin cannot be cancelled, if the authorization ends otherwise (redirect to the server or
+		// timeout) the goroutine stays blocked until ggl exits. ggl reads nothing else from stdin, so no input is lost.
+		go func() {
+			sendResult(results, pastedResult(paste, state))
+		}()
+	} else {
+		openBrowser(authURL)
+	}
+
+	var result callbackResult
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+	return config.Exchange(ctx, result.code, oauth2.VerifierOption(verifier))
+}
+
+// callbackHandler handles the redirect after the authorization. Redirects with a wrong state are rejected without
+// ending the authorization, they were not caused by the user authorizing ggl.
+func callbackHandler(logger *slog.Logger, state string, results chan<- callbackResult) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		code, err := authorizationCode(r.URL.Query(), state)
+		if errors.Is(err, errStateMismatch) {
+			logger.Warn("ignoring redirect", "err", err)
+			http.Error(w, "Invalid state", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			sendResult(results, callbackResult{err: err})
+			return
+		}
+
+		// Display a success message to the user
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprintf(w, "<html><body><h1>Authentication Successful</h1><p>You can close this window now.</p></body></html>")
+		sendResult(results, callbackResult{code: code})
+	}
+}
+
+// pastedResult reads the address the browser was redirected to from r and returns its authorization code.
+func pastedResult(r io.Reader, state string) callbackResult {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && strings.TrimSpace(line) == "" {
+		return callbackResult{err: fmt.Errorf("unable to read the redirect address: %w", err)}
+	}
+	u, err := url.Parse(strings.TrimSpace(line))
+	if err != nil {
+		return callbackResult{err: fmt.Errorf("invalid redirect address: %w", err)}
+	}
+	code, err := authorizationCode(u.Query(), state)
+	return callbackResult{code: code, err: err}
+}
+
+// authorizationCode returns the authorization code of a redirect after checking its state.
+func authorizationCode(query url.Values, state string) (string, error) {
+	if query.Get("state") != state {
+		return "", errStateMismatch
+	}
+	if e := query.Get("error"); e != "" {
+		return "", fmt.Errorf("authorization failed: %s", e)
+	}
+	code := query.Get("code")
+	if code == "" {
+		return "", errors.New("no code in callback")
+	}
+	return code, nil
+}
+
+// sendResult passes the result on unless there already is one, later redirects are dropped.
+func sendResult(results chan<- callbackResult, result callbackResult) {
+	select {
+	case results <- result:
+	default:
+	}
+}
+
+// randomState returns a random value for the state parameter of the authorization.
+func randomState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to create state: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
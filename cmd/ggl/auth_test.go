@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestAuthorizationCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		want     string
+		wantErr  string
+		mismatch bool
+	}{
+		{name: "Code", query: "state=abc&code=123", want: "123"},
+		{name: "State mismatch", query: "state=other&code=123", mismatch: true},
+		{name: "Missing state", query: "code=123", mismatch: true},
+		{name: "Error parameter", query: "state=abc&error=access_denied", wantErr: "authorization failed: access_denied"},
+		{name: "Missing code", query: "state=abc", wantErr: "no code in callback"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := authorizationCode(query, "abc")
+			if tt.mismatch != errors.Is(err, errStateMismatch) {
+				t.Fatalf("authorizationCode() error = %v, want state mismatch %v", err, tt.mismatch)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("authorizationCode() error = %v, want %q", err, tt.wantErr)
+			}
+			if tt.wantErr == "" && !tt.mismatch && err != nil {
+				t.Fatalf("authorizationCode() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("authorizationCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallbackHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantResult bool
+		wantCode   string
+		wantErr    string
+	}{
+		{name: "Code", query: "state=abc&code=123", wantStatus: http.StatusOK, wantResult: true, wantCode: "123"},
+		{name: "State mismatch is ignored", query: "state=other&code=123", wantStatus: http.StatusBadRequest},
+		{name: "Error parameter", query: "state=abc&error=access_denied", wantStatus: http.StatusBadRequest, wantResult: true, wantErr: "authorization failed: access_denied"},
+		{name: "Missing code", query: "state=abc", wantStatus: http.StatusBadRequest, wantResult: true, wantErr: "no code in callback"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make(chan callbackResult, 1)
+			handler := callbackHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), "abc", results)
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(http.MethodGet, callbackPath+"?"+tt.query, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("callbackHandler() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			select {
+			case result := <-results:
+				if !tt.wantResult {
+					t.Fatalf("callbackHandler() sent %+v, want no result", result)
+				}
+				if result.code != tt.wantCode {
+					t.Errorf("callbackHandler() code = %q, want %q", result.code, tt.wantCode)
+				}
+				if (result.err == nil) != (tt.wantErr == "") || (result.err != nil && result.err.Error() != tt.wantErr) {
+					t.Errorf("callbackHandler() error = %v, want %q", result.err, tt.wantErr)
+				}
+			default:
+				if tt.wantResult {
+					t.Errorf("callbackHandler() sent no result")
+				}
+			}
+		})
+	}
+}
+
+func TestPastedResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantCode string
+		wantErr  string
+	}{
+		{name: "Redirect address", input: "http://127.0.0.1:8080/oauth2callback?state=abc&code=123\n", wantCode: "123"},
+		{name: "Surrounding spaces without newline", input: "  http://127.0.0.1:8080/oauth2callback?state=abc&code=123  ", wantCode: "123"},
+		{name: "Empty input", input: "", wantErr: "unable to read the redirect address"},
+		{name: "Invalid address", input: "http://[::1\n", wantErr: "invalid redirect address"},
+		{name: "State mismatch", input: "http://127.0.0.1:8080/oauth2callback?state=other&code=123\n", wantErr: errStateMismatch.Error()},
+		{name: "Error parameter", input: "http://127.0.0.1:8080/oauth2callback?state=abc&error=access_denied\n", wantErr: "authorization failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pastedResult(strings.NewReader(tt.input), "abc")
+			if got.code != tt.wantCode {
+				t.Errorf("pastedResult() code = %q, want %q", got.code, tt.wantCode)
+			}
+			if (got.err == nil) != (tt.wantErr == "") || (got.err != nil && !strings.Contains(got.err.Error(), tt.wantErr)) {
+				t.Errorf("pastedResult() error = %v, want %q", got.err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenFromLoopbackPasted(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	config := &oauth2.Config{ClientID: "client", Endpoint: oauth2.Endpoint{AuthURL: "https://example.com/auth", TokenURL: "https://example.com/token"}}
+
+	t.Run("State mismatch", func(t *testing.T) {
+		paste := strings.NewReader("http://127.0.0.1:8080/oauth2callback?state=other&code=123\n")
+		_, err := tokenFromLoopback(context.Background(), logger, config, paste)
+		if !errors.Is(err, errStateMismatch) {
+			t.Errorf("tokenFromLoopback() error = %v, want %v", err, errStateMismatch)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		// nothing is pasted, the goroutine reading the pipe is released when it is closed
+		r, w := io.Pipe()
+		t.Cleanup(func() { _ = w.Close() })
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := tokenFromLoopback(ctx, logger, config, r)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("tokenFromLoopback() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
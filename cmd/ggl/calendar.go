@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"time"
+
+	gcalendar "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
+	"github.com/sascha-andres/obsidian-utils/internal"
+	"github.com/sascha-andres/obsidian-utils/internal/calendar"
+	"github.com/sascha-andres/obsidian-utils/internal/meeting"
+)
+
+// calendarTokenFile is the token file for the Calendar API, it is authorized separately from the contacts.
+const calendarTokenFile = "calendar-token.json"
+
+// exportCalendars creates a meeting note for each event of the calendars passed as -calendar within the time window
+// of -from and -to. Existing notes are kept, they are found by the uid in their frontmatter like ical -sync does or
+// by their file name. Cancelled events are skipped.
+func exportCalendars(ctx context.Context, logger *slog.Logger, names []string) error {
+	if folder == "" {
+		return errors.New("-folder must be non empty to create meeting notes")
+	}
+	if meetingFolder == "" {
+		return errors.New("-meeting-folder must be non empty to create meeting notes")
+	}
+	notesFolder, err := obsidianutils.ApplyDirectoryPlaceHolder(path.Join(folder, meetingFolder))
+	if err != nil {
+		return err
+	}
+	loc, err := internal.LoadLocation(timezone)
+	if err != nil {
+		return err
+	}
+	from, to, err := internal.TimeWindow(time.Now(), fromDate, toDate, loc)
+	if err != nil {
+		return err
+	}
+	if err := initializeStateDirectory(); err != nil {
+		return err
+	}
+
+	httpClient, err := newHTTPClient(ctx, logger, gcalendar.CalendarReadonlyScope, calendarTokenFile)
+	if err != nil {
+		return err
+	}
+	srv, err := gcalendar.NewService(ctx, option.WithHTTPClient(httpClient))
+	if err != nil {
+		return fmt.Errorf("unable to create Calendar service: %w", err)
+	}
+	client, err := calendar.NewClient(srv, calendar.WithRetries(retries, time.Second))
+	if err != nil {
+		return err
+	}
+	calendars, err := client.SelectCalendars(ctx, names)
+	if err != nil {
+		return err
+	}
+	index, err := obsidianutils.IndexFrontmatter(notesFolder, "uid")
+	if err != nil {
+		return err
+	}
+
+	var created, skipped int
+	for _, cal := range calendars {
+		events, err := client.Events(ctx, cal.Id, from, to)
+		if err != nil {
+			return err
+		}
+		logger.Debug("fetched events", "calendar", cal.Summary, "count", len(events))
+		for _, e := range events {
+			if calendar.IsCancelled(e) || (skipAllDay && calendar.IsAllDay(e)) {
+				logger.Debug("skipping event", "summary", e.Summary, "cancelled", calendar.IsCancelled(e))
+				continue
+			}
+			ok, err := writeMeetingNote(logger, notesFolder, index, e, loc)
+			if err != nil {
+				return err
+			}
+			if ok {
+				created++
+			} else {
+				skipped++
+			}
+		}
+	}
+	logger.Info("wrote meeting notes", "folder", notesFolder, "created", created, "existing", skipped)
+	return nil
+}
+
+// writeMeetingNote creates the meeting note of the event, it reports false if the note already exists. index maps the
+// uids of the existing notes to their files, the created note is added.
+func writeMeetingNote(logger *slog.Logger, notesFolder string, index map[string]string, e *gcalendar.Event, loc *time.Location) (bool, error) {
+	if fileName, ok := index[calendar.Key(e)]; ok {
+		logger.Debug("skipping existing note", "uid", calendar.Key(e), "file", fileName)
+		return false, nil
+	}
+	start, err := calendar.Start(e, loc)
+	if err != nil {
+		return false, err
+	}
+	opts, err := calendar.Options(e, loc)
+	if err != nil {
+		return false, err
+	}
+	m, err := meeting.NewMeeting(append(opts, meeting.WithTemplate(templateFile), meeting.WithAttendeeLinks(attendeeLinks))...)
+	if err != nil {
+		return false, err
+	}
+	fullName, err := obsidianutils.CreateFileName(notesFolder, e.Summary, noDatePrefix, start)
+	if err != nil {
+		return false, err
+	}
+	exists, err := internal.Exists(fullName)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		logger.Debug("skipping existing file", "file", fullName)
+		return false, nil
+	}
+	c, err := m.CreateContent(e.Summary, start)
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(notesFolder, 0700); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(fullName, []byte(c), 0600); err != nil {
+		return false, err
+	}
+	index[calendar.Key(e)] = fullName
+	logger.Info("created meeting", "summary", e.Summary, "start", start, "file", fullName)
+	return true, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"google.golang.org/api/people/v1"
+
+	"github.com/sascha-andres/obsidian-utils/internal/contacts"
+)
+
+// formatFiles maps the export formats to the files the contacts are written to.
+var formatFiles = map[string]string{
+	"json":      "contacts.json",
+	"flat":      "contacts.flat.json",
+	"vcard":     "contacts.vcf",
+	"csv":       "contacts.csv",
+	"combined":  "combined.json",
+	"birthdays": "birthdays.md",
+}
+
+// exportFormats returns the formats passed as -format, validated and without duplicates.
+func exportFormats() ([]string, error) {
+	var result []string
+	for _, f := range nonEmpty(formats()) {
+		if _, ok := formatFiles[f]; !ok {
+			return nil, fmt.Errorf("invalid format %q, expected json, flat, vcard, csv, combined or birthdays", f)
+		}
+		if !slices.Contains(result, f) {
+			result = append(result, f)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("-format must be non empty")
+	}
+	return result, nil
+}
+
+// encodeContacts returns the contacts in the export format. The group names are used by all formats but json.
+func encodeContacts(format string, connections []*people.Person, groups []*people.ContactGroup) ([]byte, error) {
+	groupNames := contacts.GroupNames(groups)
+	var buf bytes.Buffer
+	switch format {
+	case "flat":
+		data, err := json.MarshalIndent(contacts.Flatten(connections, groupNames), "", "  ")
+		return data, err
+	case "vcard":
+		err := contacts.WriteVCards(&buf, connections, groupNames)
+		return buf.Bytes(), err
+	case "combined":
+		return json.MarshalIndent(contacts.Combine(connections, groups), "", "  ")
+	case "birthdays":
+		err := contacts.WriteBirthdays(&buf, connections)
+		return buf.Bytes(), err
+	case "csv":
+		err := contacts.WriteCSV(&buf, connections, groupNames)
+		return buf.Bytes(), err
+	}
+	return json.MarshalIndent(connections, "", "  ")
+}
+
+// nonEmpty returns the values of a list flag without empty entries.
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			result = append(result, strings.TrimSpace(v))
+		}
+	}
+	return result
+}
+
+// readExport reads the contacts.json and groups.json in dir, written by an earlier run. A missing groups.json is
+// only logged, the contacts are then exported without groups.
+func readExport(logger *slog.Logger, dir string) ([]*people.Person, []*people.ContactGroup, error) {
+	connections, err := contacts.ReadContacts(path.Join(dir, "contacts.json"))
+	if err != nil {
+		return nil, nil, err
+	}
+	groups, err := contacts.ReadGroups(path.Join(dir, "groups.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		logger.Warn("no groups exported", "directory", dir)
+		return connections, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Debug("read export", "directory", dir, "contacts", len(connections), "groups", len(groups))
+	return connections, groups, nil
+}
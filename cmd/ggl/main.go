@@ -14,6 +14,9 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/sascha-andres/reuse/flag"
 	"golang.org/x/oauth2"
@@ -23,6 +26,7 @@ import (
 
 	obsidianutils "github.com/sascha-andres/obsidian-utils"
 	"github.com/sascha-andres/obsidian-utils/internal"
+	"github.com/sascha-andres/obsidian-utils/internal/contacts"
 )
 
 // Scopes required for reading contacts and contact groups
@@ -30,7 +34,15 @@ const contactsScope = "https://www.googleapis.com/auth/contacts.readonly"
 
 var (
 	stateDirectory, outputDirectory, printToConsole, logLevel string
-	verbose                                                   bool
+	folder, personFolder, personFields                        string
+	authFlow, account                                         string
+	meetingFolder, templateFile, timezone, fromDate, toDate   string
+	reportFormat, inputDirectory                              string
+	attendeeLinks, noDatePrefix, skipAllDay, apply            bool
+	verbose, incremental                                      bool
+	retries                                                   int
+	authTimeout                                               time.Duration
+	formats, mergeFrom, calendars                             func() []string
 )
 
 // init initializes the program's environment settings and configuration for Google-related utilities.
@@ -46,15 +58,37 @@ func init() {
 	flag.StringVar(&printToConsole, "print-to-console", "", "Print data to console instead of writing to files, may be contacts or groups")
 	flag.StringVar(&logLevel, "log-level", "info", "Log level, one of: debug, info, warn, error, fatal")
 	flag.BoolVar(&verbose, "verbose", false, "Enable verbose output")
+	flag.StringVar(&folder, "folder", "", "base path of obsidian vault, required for -person-folder")
+	flag.StringVar(&personFolder, "person-folder", "", "where to create or update a note per contact inside the vault")
+	flag.StringVar(&personFields, "fields", contacts.DefaultPersonFields, "Comma separated list of person fields to export or all")
+	formats = flag.StringSliceVar("format", []string{"json"}, "Comma separated list of formats to export contacts in: json, flat, vcard, csv, combined, birthdays")
+	flag.BoolVar(&incremental, "incremental", false, "Fetch only the contacts changed since the last run and apply them to the export")
+	flag.IntVar(&retries, "retries", 5, "Number of retries for requests failing because of rate limits or server errors")
+	flag.StringVar(&authFlow, "auth-flow", browserFlow, "How to authorize ggl: browser or manual (paste the redirect address, e.g. over SSH)")
+	flag.StringVar(&account, "account", "", "Name of the Google account to use, each account has its own token and output directory")
+	mergeFrom = flag.StringSliceVar("merge", []string{}, "Comma separated list of accounts whose exports are merged into the output directory")
+	calendars = flag.StringSliceVar("calendar", []string{}, "Comma separated list of calendars (name, id or primary) to create meeting notes from instead of exporting contacts")
+	flag.StringVar(&meetingFolder, "meeting-folder", "", "where to create the meeting notes inside the vault, required for -calendar")
+	flag.StringVar(&templateFile, "template-file", "", "path to template file for meeting notes")
+	flag.StringVar(&timezone, "timezone", "", "timezone to write meeting times in, e.g. Europe/Berlin (default: local)")
+	flag.StringVar(&fromDate, "from", "", "first day of the time window for events (2006-01-02 or +-offset, default: now)")
+	flag.StringVar(&toDate, "to", "+14", "last day of the time window for events (2006-01-02 or +-offset)")
+	flag.BoolVar(&attendeeLinks, "attendee-links", false, "pass to write attendees as links to person notes")
+	flag.BoolVar(&noDatePrefix, "no-date-prefix", false, "pass to not add yyyy-mm-dd prefix to meeting note file names")
+	flag.BoolVar(&skipAllDay, "skip-all-day", false, "pass to skip all-day events")
+	flag.StringVar(&inputDirectory, "input-directory", "", "Directory with a contacts.json and groups.json to transform instead of fetching from Google")
+	flag.StringVar(&reportFormat, "report", "", "Print how the person notes differ from the exported contacts as markdown or json instead of exporting")
+	flag.BoolVar(&apply, "apply", false, "With -report, write the values of the contacts without conflict into the person notes")
+	flag.DurationVar(&authTimeout, "auth-timeout", 5*time.Minute, "Time to complete the authorization")
 }
 
-// getClient retrieves a token, saves it, then returns the OAuth2 client
-func getClient(logger *slog.Logger, config *oauth2.Config) (*http.Client, error) {
-	// The file token.json stores the user's access and refresh tokens
-	tokenFile := path.Join(stateDirectory, "token.json")
+// getClient retrieves a token, saves it, then returns the OAuth2 client. Refreshed tokens are saved as well.
+func getClient(ctx context.Context, logger *slog.Logger, config *oauth2.Config, tokenName string) (*http.Client, error) {
+	// The token file stores the user's access and refresh tokens
+	tokenFile := path.Join(accountStateDirectory(), tokenName)
 	tok, err := tokenFromFile(logger, tokenFile)
 	if err != nil {
-		tok, err = getTokenFromWeb(config)
+		tok, err = getTokenFromWeb(ctx, logger, config)
 		if err != nil {
 			return nil, err
 		}
@@ -63,7 +97,7 @@ func getClient(logger *slog.Logger, config *oauth2.Config) (*http.Client, error)
 			return nil, err
 		}
 	}
-	return config.Client(context.Background(), tok), nil
+	return oauth2.NewClient(ctx, oauth2.ReuseTokenSource(tok, newSavingTokenSource(ctx, logger, config, tok, tokenFile))), nil
 }
 
 // openBrowser opens a browser window with the specified URL
@@ -90,80 +124,6 @@ func openBrowser(url string) {
 	}
 }
 
-// getTokenFromWeb requests a token from the web by starting a local web server to handle the OAuth callback
-func getTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
-	// Set up a local web server to receive the callback
-	codeChan := make(chan string)
-	errChan := make(chan error)
-
-	// Create a random port between 8000 and 9000
-	const callbackPath = "/oauth2callback"
-	redirectURL := "http://localhost:8080" + callbackPath
-	config.RedirectURL = redirectURL
-
-	// Set up the server
-	server := &http.Server{Addr: ":8080"}
-
-	// Set up the handler
-	http.HandleFunc(callbackPath, func(w http.ResponseWriter, r *http.Request) {
-		code := r.URL.Query().Get("code")
-		if code == "" {
-			errChan <- fmt.Errorf("no code in callback")
-			http.Error(w, "No code provided", http.StatusBadRequest)
-			return
-		}
-
-		// Display a success message to the user
-		w.Header().Set("Content-Type", "text/html")
-		_, _ = fmt.Fprintf(w, "<html><body><h1>Authentication Successful</h1><p>You can close this window now.</p></body></html>")
-
-		// Send the code to the channel
-		codeChan <- code
-	})
-
-	// Start the server in a goroutine
-	go func() {
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			errChan <- err
-		}
-	}()
-
-	// Generate the auth URL
-	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-
-	// Prompt the user to open the URL
-	fmt.Println("==========================================================")
-	fmt.Println("To authorize this application:")
-	fmt.Println("1. A browser window should open automatically.")
-	fmt.Println("   If it doesn't, please open the following URL:")
-	fmt.Printf("   %v\n", authURL)
-	fmt.Println("2. Sign in and grant access to your Google account")
-	fmt.Println("==========================================================")
-
-	// Try to open the browser automatically
-	openBrowser(authURL)
-
-	// Wait for the code or an error
-	var code string
-	select {
-	case code = <-codeChan:
-		// Got the code, continue
-	case err := <-errChan:
-		// Shutdown the server
-		_ = server.Shutdown(context.Background())
-		return nil, err
-	}
-
-	// Shutdown the server
-	err := server.Shutdown(context.Background())
-	if err != nil {
-		log.Printf("Error shutting down server: %v", err)
-	}
-
-	// Exchange the code for a token
-	return config.Exchange(context.TODO(), code)
-}
-
 // tokenFromFile retrieves a token from a local file
 func tokenFromFile(logger *slog.Logger, file string) (*oauth2.Token, error) {
 	f, err := os.Open(file)
@@ -199,10 +159,10 @@ func saveToken(logger *slog.Logger, path string, token *oauth2.Token) error {
 	return json.NewEncoder(f).Encode(token)
 }
 
-// initializeStateDirectory ensures the state directory exists, creating it with the correct permissions if it does not exist.
+// initializeStateDirectory ensures the state directory of the account exists, creating it with the correct permissions if it does not exist.
 func initializeStateDirectory() error {
-	if _, err := os.Stat(stateDirectory); os.IsNotExist(err) {
-		err := os.MkdirAll(stateDirectory, 0700)
+	if _, err := os.Stat(accountStateDirectory()); os.IsNotExist(err) {
+		err := os.MkdirAll(accountStateDirectory(), 0700)
 		if err != nil {
 			return err
 		}
@@ -242,36 +202,177 @@ func run(logger *slog.Logger) error {
 	if err != nil {
 		return err
 	}
+	if incremental && printToConsole != "" {
+		return errors.New("-incremental can not be combined with -print-to-console")
+	}
+	exportAs, err := exportFormats()
+	if err != nil {
+		return err
+	}
+	if incremental && !slices.Contains(exportAs, "json") {
+		return errors.New("-incremental requires the json format, the changes are applied to contacts.json")
+	}
+	merge := nonEmpty(mergeFrom())
+	for _, name := range append(slices.Clone(merge), account) {
+		if name == "" {
+			continue
+		}
+		if err := validateAccount(name); err != nil {
+			return err
+		}
+	}
+	if len(merge) > 0 && (account != "" || incremental) {
+		return errors.New("-merge can not be combined with -account or -incremental")
+	}
+	if inputDirectory != "" && (len(merge) > 0 || incremental) {
+		return errors.New("-input-directory can not be combined with -merge or -incremental")
+	}
+	if selected := nonEmpty(calendars()); len(selected) > 0 {
+		if len(merge) > 0 || incremental || inputDirectory != "" {
+			return errors.New("-calendar can not be combined with -merge, -incremental or -input-directory")
+		}
+		return exportCalendars(ctx, logger, selected)
+	}
+	var notesFolder string
+	if personFolder != "" {
+		if folder == "" {
+			return errors.New("-folder must be non empty to create person notes")
+		}
+		if notesFolder, err = obsidianutils.ApplyDirectoryPlaceHolder(path.Join(folder, personFolder)); err != nil {
+			return err
+		}
+	}
+	if reportFormat != "" {
+		readFrom := accountOutputDirectory(writeTo)
+		if inputDirectory != "" {
+			if readFrom, err = obsidianutils.ApplyDirectoryPlaceHolder(inputDirectory); err != nil {
+				return err
+			}
+		}
+		return reportContacts(logger, readFrom, notesFolder, reportFormat)
+	}
+	if apply {
+		return errors.New("-apply requires -report")
+	}
+
+	// offline modes transform existing exports without accessing Google, -merge can not be combined with -account
+	if inputDirectory != "" || len(merge) > 0 {
+		writeTo = accountOutputDirectory(writeTo)
+		if err = initializeOutputDirectory(writeTo); err != nil {
+			return err
+		}
+		var (
+			connections []*people.Person
+			groups      []*people.ContactGroup
+		)
+		if inputDirectory != "" {
+			readFrom, err := obsidianutils.ApplyDirectoryPlaceHolder(inputDirectory)
+			if err != nil {
+				return err
+			}
+			connections, groups, err = readExport(logger, readFrom)
+			if err != nil {
+				return err
+			}
+		} else if connections, groups, err = mergeAccounts(logger, writeTo, merge); err != nil {
+			return err
+		}
+		return export(logger, connections, groups, writeTo, notesFolder, exportAs, nil)
+	}
+
+	writeTo = accountOutputDirectory(writeTo)
 	err = initializeEnvironment(logger, writeTo)
 	if err != nil {
 		return err
 	}
-	srv, err := initializeGoogleApiClient(logger, ctx)
+	fetched, err := fetchContacts(ctx, logger, writeTo, notesFolder, exportAs)
 	if err != nil {
 		return err
 	}
-	if printToConsole == "" || printToConsole == "contacts" {
-		err = handleContacts(srv, writeTo)
-		if err != nil {
-			return err
-		}
+	if err = export(logger, fetched.connections, fetched.groups, writeTo, notesFolder, exportAs, fetched.summary.DeletedResourceNames); err != nil {
+		return err
 	}
-	if printToConsole != "" && printToConsole != "groups" {
+	if !incremental {
 		return nil
 	}
-	return handleGroups(srv, writeTo)
+	summary := fetched.summary
+	if summary.IsEmpty() {
+		fmt.Println("no contacts changed")
+	} else {
+		fmt.Printf("%d added, %d updated, %d deleted\n%s", len(summary.Added), len(summary.Updated), len(summary.Deleted), summary)
+	}
+	return saveSyncToken(fetched.syncToken, fetched.personFields)
 }
 
-// handleGroups retrieves Google Contact Groups, exporting data as JSON either by printing to the console or saving to a file.
-func handleGroups(srv *people.Service, writeTo string) error {
-	// List contact groups
-	groups, err := srv.ContactGroups.List().PageSize(1000).Do()
+// fetched holds what fetchContacts got from Google.
+type fetched struct {
+	connections  []*people.Person
+	groups       []*people.ContactGroup
+	summary      contacts.Summary
+	syncToken    string
+	personFields string
+}
+
+// fetchContacts fetches the contacts and groups needed for the export from Google. With -incremental only the
+// changes are fetched and applied to the contacts.json in writeTo.
+func fetchContacts(ctx context.Context, logger *slog.Logger, writeTo, notesFolder string, exportAs []string) (fetched, error) {
+	var result fetched
+	srv, err := initializeGoogleApiClient(logger, ctx)
 	if err != nil {
-		return fmt.Errorf("unable to retrieve contact groups: %w", err)
+		return result, err
 	}
+	client, err := contacts.NewClient(srv,
+		contacts.WithPersonFields(personFields),
+		contacts.WithRetries(retries, time.Second),
+		contacts.WithProgress(func(kind string, fetched int) {
+			logger.Info("fetching", "kind", kind, "fetched", fetched)
+		}))
+	if err != nil {
+		return result, err
+	}
+	result.personFields = client.PersonFields()
+	if incremental {
+		if result.connections, result.summary, result.syncToken, err = syncContacts(ctx, logger, client, writeTo); err != nil {
+			return result, err
+		}
+	} else if printToConsole == "" || printToConsole == "contacts" || notesFolder != "" {
+		if result.connections, err = client.Connections(ctx); err != nil {
+			return result, err
+		}
+	}
+	if printToConsole != "contacts" || notesFolder != "" || slices.ContainsFunc(exportAs, func(f string) bool { return f != "json" }) {
+		if result.groups, err = client.Groups(ctx); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// export writes the contacts and groups to writeTo, or prints them, and writes the person notes. deleted are the
+// resource names of contacts deleted since the last run.
+func export(logger *slog.Logger, connections []*people.Person, groups []*people.ContactGroup, writeTo, notesFolder string, formats, deleted []string) error {
+	if printToConsole == "" || printToConsole == "contacts" {
+		if err := handleContacts(connections, groups, writeTo, formats); err != nil {
+			return err
+		}
+	}
+	if printToConsole == "" || printToConsole == "groups" {
+		if err := handleGroups(groups, writeTo); err != nil {
+			return err
+		}
+	}
+	if notesFolder != "" {
+		if err := writePersonNotes(logger, notesFolder, connections, groups, deleted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// handleGroups exports Google Contact Groups as JSON either by printing to the console or saving to a file.
+func handleGroups(groups []*people.ContactGroup, writeTo string) error {
 	// Marshal groups data to JSON
-	groupsJsonData, err := json.MarshalIndent(groups.ContactGroups, "", "  ")
+	groupsJsonData, err := json.MarshalIndent(groups, "", "  ")
 	if err != nil {
 		return fmt.Errorf("unable to marshal groups to JSON: %w", err)
 	}
@@ -288,51 +389,59 @@ func handleGroups(srv *people.Service, writeTo string) error {
 
 		groupsAbsPath, _ := filepath.Abs(groupsOutputFile)
 		if verbose {
-			fmt.Printf("Successfully exported %d groups to %s\n", len(groups.ContactGroups), groupsAbsPath)
+			fmt.Printf("Successfully exported %d groups to %s\n", len(groups), groupsAbsPath)
 		}
 	}
 	return nil
 }
 
-// handleContacts retrieves and processes Google Contacts, exporting data to JSON either by printing or saving to a file.
-func handleContacts(srv *people.Service, writeTo string) error {
-	// List connections (contacts)
-	r, err := srv.People.Connections.List("people/me").
-		PersonFields("names,emailAddresses,phoneNumbers,addresses,organizations,memberships,birthdays").
-		PageSize(1000).
-		Do()
-	if err != nil {
-		return fmt.Errorf("unable to retrieve contacts: %w", err)
-	}
-
-	// Marshal contacts data to JSON
-	jsonData, err := json.MarshalIndent(r.Connections, "", "  ")
-	if err != nil {
-		return fmt.Errorf("unable to marshal contacts to JSON: %w", err)
-	}
+// handleContacts exports Google Contacts in the requested formats either by printing or saving to files.
+func handleContacts(connections []*people.Person, groups []*people.ContactGroup, writeTo string, formats []string) error {
+	for _, format := range formats {
+		data, err := encodeContacts(format, connections, groups)
+		if err != nil {
+			return fmt.Errorf("unable to encode contacts as %s: %w", format, err)
+		}
 
-	if printToConsole == "contacts" {
-		fmt.Println(string(jsonData))
-	} else if printToConsole == "" {
+		if printToConsole == "contacts" {
+			fmt.Println(strings.TrimRight(string(data), "\r\n"))
+			continue
+		}
 		// Write contacts data to a file
-		outputFile := path.Join(writeTo, "contacts.json")
-		err = os.WriteFile(outputFile, jsonData, 0644)
+		outputFile := path.Join(writeTo, formatFiles[format])
+		err = os.WriteFile(outputFile, data, 0644)
 		if err != nil {
 			return fmt.Errorf("unable to write contacts to file: %w", err)
 		}
 
 		absPath, _ := filepath.Abs(outputFile)
 		if verbose {
-			fmt.Printf("Successfully exported %d contacts to %s\n", len(r.Connections), absPath)
+			fmt.Printf("Successfully exported %d contacts to %s\n", len(connections), absPath)
 		}
 	}
-	return err
+	return nil
 }
 
 // initializeGoogleApiClient initializes and returns a Google People Service client using OAuth2.
 func initializeGoogleApiClient(logger *slog.Logger, ctx context.Context) (*people.Service, error) {
+	client, err := newHTTPClient(ctx, logger, contactsScope, "token.json")
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the People service
+	srv, err := people.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create People service: %w", err)
+	}
+	return srv, nil
+}
+
+// newHTTPClient returns an HTTP client authorized for the scope. The token is stored in the token file of the
+// account, each scope has its own token file, so authorizing one scope does not invalidate the tokens of others.
+func newHTTPClient(ctx context.Context, logger *slog.Logger, scope, tokenFile string) (*http.Client, error) {
 	// Check if credentials.json exists
-	credFile := path.Join(stateDirectory, "credentials.json")
+	credFile := credentialsFile()
 	if _, err := os.Stat(credFile); os.IsNotExist(err) {
 		return nil, fmt.Errorf("missing credentials file: %s\nPlease download it from Google Cloud Console", credFile)
 	}
@@ -343,21 +452,15 @@ func initializeGoogleApiClient(logger *slog.Logger, ctx context.Context) (*peopl
 	}
 
 	// Configure the OAuth2 client
-	config, err := google.ConfigFromJSON(b, contactsScope)
+	config, err := google.ConfigFromJSON(b, scope)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse client secret file to config: %w", err)
 	}
-	client, err := getClient(logger, config)
+	client, err := getClient(ctx, logger, config, tokenFile)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve token: %w", err)
 	}
-
-	// Create the People service
-	srv, err := people.NewService(ctx, option.WithHTTPClient(client))
-	if err != nil {
-		return nil, fmt.Errorf("unable to create People service: %w", err)
-	}
-	return srv, nil
+	return client, nil
 }
 
 // initializeEnvironment sets up the necessary directories for application state and output, returning an error on failure.
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// offlineFlags sets the flags of an export of testdata/export, the flags are reset when the test ends.
+func offlineFlags(t *testing.T, writeTo string, exportAs []string) {
+	t.Helper()
+	saved := []string{outputDirectory, inputDirectory, account, folder, personFolder, printToConsole, reportFormat}
+	savedFormats := formats
+	t.Cleanup(func() {
+		outputDirectory, inputDirectory, account, folder, personFolder, printToConsole, reportFormat =
+			saved[0], saved[1], saved[2], saved[3], saved[4], saved[5], saved[6]
+		formats = savedFormats
+	})
+	outputDirectory, inputDirectory = writeTo, filepath.Join("testdata", "export")
+	account, folder, personFolder, printToConsole, reportFormat = "", "", "", "", ""
+	formats = func() []string { return exportAs }
+}
+
+func TestOfflineExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		formats  []string
+		account  string
+		notes    bool
+		contains map[string][]string
+	}{
+		{
+			name:    "json",
+			formats: []string{"json"},
+			contains: map[string][]string{
+				"contacts.json": {`"resourceName": "people/c1"`, `"resourceName": "people/c2"`},
+				"groups.json":   {`"name": "Book Club"`},
+			},
+		},
+		{
+			name:     "vcard",
+			formats:  []string{"vcard"},
+			contains: map[string][]string{"contacts.vcf": {"UID:people/c1", "FN:Alice Example", "CATEGORIES:Book Club", "FN:Bob"}},
+		},
+		{
+			name:     "csv",
+			formats:  []string{"csv"},
+			contains: map[string][]string{"contacts.csv": {"resource name,name,", "people/c1,Alice Example,Alice,Example", "people/c2,Bob"}},
+		},
+		{
+			name:     "combined",
+			formats:  []string{"combined"},
+			contains: map[string][]string{"combined.json": {`"name": "Book Club"`, `"resourceName": "people/c1"`}},
+		},
+		{
+			name:     "birthdays",
+			formats:  []string{"birthdays"},
+			contains: map[string][]string{"birthdays.md": {"## April\n\n- 04-07 [[Alice Example]] (1980)\n- 04-12 [[Bob]]\n"}},
+		},
+		{
+			name:     "Several formats of an account",
+			formats:  []string{"vcard", "birthdays"},
+			account:  "work",
+			contains: map[string][]string{"work/contacts.vcf": {"FN:Alice Example"}, "work/birthdays.md": {"[[Bob]]"}},
+		},
+		{
+			name:    "Person notes",
+			formats: []string{"json"},
+			notes:   true,
+			contains: map[string][]string{
+				"vault/People/Alice Example.md": {"resource name: people/c1\n", "emails:\n- alice@example.com\n", "groups:\n- '[[Book Club]]'\n", "# Alice Example\n"},
+				"vault/People/Bob.md":           {"resource name: people/c2\n", "birthday: --04-12\n"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			offlineFlags(t, dir, tt.formats)
+			account = tt.account
+			if tt.notes {
+				folder, personFolder = filepath.Join(dir, "vault"), "People"
+			}
+			if err := run(slog.New(slog.NewTextHandler(io.Discard, nil))); err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			for file, want := range tt.contains {
+				data, err := os.ReadFile(filepath.Join(dir, file))
+				if err != nil {
+					t.Fatalf("run() did not write %s: %v", file, err)
+				}
+				for _, w := range want {
+					if !strings.Contains(string(data), w) {
+						t.Errorf("run() wrote %s = %q, want it to contain %q", file, data, w)
+					}
+				}
+			}
+			if tt.account != "" {
+				if _, err := os.Stat(filepath.Join(dir, "contacts.vcf")); err == nil {
+					t.Errorf("run() wrote contacts.vcf outside of the account directory")
+				}
+			}
+		})
+	}
+}
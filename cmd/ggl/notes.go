@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"google.golang.org/api/people/v1"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
+	"github.com/sascha-andres/obsidian-utils/internal"
+	"github.com/sascha-andres/obsidian-utils/internal/contacts"
+)
+
+// writePersonNotes creates a note for each contact in the folder. Existing notes only get their frontmatter updated,
+// so text written into them is kept. Notes are found by the resource name of the contact, so renamed contacts keep
+// their note, or else by name. Contacts sharing a name with a note of another contact are skipped. The notes of the
+// deleted contacts are flagged as deleted.
+func writePersonNotes(logger *slog.Logger, notesFolder string, connections []*people.Person, groups []*people.ContactGroup, deleted []string) error {
+	if err := os.MkdirAll(notesFolder, 0700); err != nil {
+		return err
+	}
+	index, err := obsidianutils.IndexFrontmatter(notesFolder, contacts.ResourceNameKey)
+	if err != nil {
+		return err
+	}
+	groupNames := contacts.GroupNames(groups)
+	keys := contacts.ManagedKeys(personFields)
+	now := time.Now()
+	var created, updated, flagged int
+	for _, p := range connections {
+		fileName, ok := index[p.ResourceName]
+		if !ok {
+			if fileName, err = contacts.NoteFileName(notesFolder, p); err != nil {
+				logger.Warn("skipping contact", "err", err)
+				continue
+			}
+		}
+		exists, err := internal.Exists(fileName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			data, err := contacts.NewNote(p, groupNames, now)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(fileName, data, 0600); err != nil {
+				return err
+			}
+			logger.Debug("created person note", "file", fileName)
+			created++
+			continue
+		}
+		changed, err := contacts.UpdateNote(fileName, p, groupNames, keys, now)
+		if errors.Is(err, contacts.ErrOtherContact) {
+			logger.Warn("skipping contact", "name", contacts.DisplayName(p), "err", err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to update %s: %w", fileName, err)
+		}
+		if changed {
+			logger.Debug("updated person note", "file", fileName)
+			updated++
+		}
+	}
+	for _, resourceName := range deleted {
+		fileName, ok := index[resourceName]
+		if !ok {
+			continue
+		}
+		changed, err := contacts.MarkDeleted(fileName, now)
+		if err != nil {
+			return fmt.Errorf("unable to update %s: %w", fileName, err)
+		}
+		if changed {
+			logger.Debug("flagged person note of deleted contact", "file", fileName)
+			flagged++
+		}
+	}
+	logger.Info("wrote person notes", "folder", notesFolder, "created", created, "updated", updated, "deleted", flagged)
+	return nil
+}
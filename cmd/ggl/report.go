@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sascha-andres/obsidian-utils/internal/contacts"
+)
+
+// reportContacts prints how the person notes in notesFolder differ from the contacts exported to readFrom, as markdown
+// or json. With -apply the differences without conflict are written into the notes. Nothing is fetched from Google.
+func reportContacts(logger *slog.Logger, readFrom, notesFolder, format string) error {
+	if format != "markdown" && format != "json" {
+		return fmt.Errorf("invalid report format %q, expected markdown or json", format)
+	}
+	if notesFolder == "" {
+		return errors.New("-folder and -person-folder must be non empty for a report")
+	}
+	connections, groups, err := readExport(logger, readFrom)
+	if err != nil {
+		return fmt.Errorf("unable to read the export, run ggl without -report first: %w", err)
+	}
+	report, err := contacts.Reconcile(notesFolder, connections, contacts.GroupNames(groups), contacts.ManagedKeys(personFields))
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Print(report.Markdown())
+	}
+
+	if !apply {
+		return nil
+	}
+	changed, err := contacts.Apply(report, time.Now())
+	if err != nil {
+		return err
+	}
+	logger.Info("applied updates without conflict", "notes", changed, "conflicts", report.Conflicts())
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"google.golang.org/api/people/v1"
+
+	"github.com/sascha-andres/obsidian-utils/internal/contacts"
+)
+
+// syncTokenFile returns the file storing the sync token of the last incremental run of the account.
+func syncTokenFile() string {
+	return path.Join(accountStateDirectory(), "contacts-sync-token")
+}
+
+// syncContacts fetches the contacts changed since the last run and applies them to the contacts.json in writeTo.
+// All contacts are fetched if there is no sync token or export yet or if the sync token expired. It returns the
+// contacts, the changes and the sync token to store once the contacts are written.
+func syncContacts(ctx context.Context, logger *slog.Logger, client *contacts.Client, writeTo string) ([]*people.Person, contacts.Summary, string, error) {
+	previous, err := contacts.ReadContacts(path.Join(writeTo, "contacts.json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, contacts.Summary{}, "", err
+	}
+	token, tokenErr := readSyncToken(logger, client.PersonFields())
+	if tokenErr != nil {
+		return nil, contacts.Summary{}, "", tokenErr
+	}
+
+	if err == nil && token != "" {
+		changes, next, err := client.Changes(ctx, token)
+		if err == nil {
+			logger.Debug("fetched changed contacts", "changes", len(changes))
+			result, summary := contacts.ApplyChanges(previous, changes)
+			return withoutSyncMetadata(client, result), summary, next, nil
+		}
+		if !errors.Is(err, contacts.ErrSyncTokenExpired) {
+			return nil, contacts.Summary{}, "", err
+		}
+		logger.Warn("sync token expired, fetching all contacts")
+	} else {
+		logger.Info("no previous export or sync token, fetching all contacts")
+	}
+
+	all, next, err := client.Sync(ctx)
+	if err != nil {
+		return nil, contacts.Summary{}, "", err
+	}
+	all = withoutSyncMetadata(client, all)
+	return all, contacts.Compare(previous, all), next, nil
+}
+
+// readSyncToken returns the sync token of the last incremental run. The token is only valid for the same person
+// fields, an empty token is returned if they changed.
+func readSyncToken(logger *slog.Logger, fields string) (string, error) {
+	data, err := os.ReadFile(syncTokenFile())
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	token, tokenFields, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+	if strings.TrimSpace(tokenFields) != fields {
+		logger.Info("person fields changed since the last run", "previous", strings.TrimSpace(tokenFields), "current", fields)
+		return "", nil
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// saveSyncToken stores the sync token for the next incremental run, followed by the person fields it is valid for.
+func saveSyncToken(token, fields string) error {
+	if token == "" {
+		return errors.New("no sync token received")
+	}
+	return os.WriteFile(syncTokenFile(), []byte(token+"\n"+fields+"\n"), 0600)
+}
+
+// withoutSyncMetadata removes the metadata requested for syncing from the contacts, unless it was asked for.
+func withoutSyncMetadata(client *contacts.Client, connections []*people.Person) []*people.Person {
+	if slices.Contains(strings.Split(client.PersonFields(), ","), "metadata") {
+		return connections
+	}
+	return contacts.WithoutMetadata(connections)
+}
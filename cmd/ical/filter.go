@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sascha-andres/obsidian-utils/internal/ical"
+)
+
+// newEventFilter creates the filter deciding which events become notes from the command line flags.
+func newEventFilter() (ical.Filter, error) {
+	f := ical.Filter{
+		SkipAllDay:        skipAllDay,
+		SkipDeclined:      skipDeclined,
+		MyEmails:          nonEmpty(myEmails()),
+		MinAttendees:      minAttendees,
+		Categories:        nonEmpty(categories()),
+		ExcludeCategories: nonEmpty(excludeCategories()),
+		Calendars:         nonEmpty(calendars()),
+	}
+	var err error
+	if includeTitle != "" {
+		if f.Include, err = regexp.Compile(includeTitle); err != nil {
+			return f, fmt.Errorf("invalid -include: %w", err)
+		}
+	}
+	if excludeTitle != "" {
+		if f.Exclude, err = regexp.Compile(excludeTitle); err != nil {
+			return f, fmt.Errorf("invalid -exclude: %w", err)
+		}
+	}
+	if f.SkipDeclined && len(f.MyEmails) == 0 {
+		return f, errors.New("-skip-declined requires -my-email")
+	}
+	return f, nil
+}
+
+// nonEmpty returns the values of a list flag without empty entries.
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			result = append(result, strings.TrimSpace(v))
+		}
+	}
+	return result
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/apognu/gocal"
@@ -13,13 +14,23 @@ import (
 
 	obsidianutils "github.com/sascha-andres/obsidian-utils"
 	"github.com/sascha-andres/obsidian-utils/internal"
+	"github.com/sascha-andres/obsidian-utils/internal/ical"
 	"github.com/sascha-andres/obsidian-utils/internal/meeting"
 )
 
 var (
 	folder, meetingFolder, icalFile          string
+	stdinCalendar                            string
+	templateFile, fromDate, toDate           string
+	includeTitle, excludeTitle, timezone     string
+	dailyFolder, dailyHeadline               string
 	logLevel                                 string
 	noDatePrefix, printConfig, dryRun, force bool
+	attendeeLinks, syncNotes, series         bool
+	skipAllDay, skipDeclined, linkDailyNote  bool
+	minAttendees                             int
+	myEmails, categories, excludeCategories  func() []string
+	calendars                                func() []string
 )
 
 // init initializes the package by setting up flag options, log flags, and prefix.
@@ -27,6 +38,10 @@ func init() {
 	internal.AddCommonFlagPrefixes()
 	flag.SetEnvPrefix("OBS_UTIL_ICAL")
 	flag.SetEnvPrefixForFlag("meeting-folder", "OBS_UTIL_AM")
+	flag.SetEnvPrefixForFlag("template-file", "OBS_UTIL_AM")
+	flag.SetEnvPrefixForFlag("timezone", "OBS_UTIL_AM")
+	flag.SetEnvPrefixForFlag("link-daily", "OBS_UTIL_AM")
+	flag.SetEnvPrefixForFlag("daily-headline", "OBS_UTIL_AM")
 	flag.StringVar(&logLevel, "log-level", "info", "pass log level (debug/info/warn/error)")
 	flag.StringVar(&folder, "folder", "", "base path of obsidian vault")
 	flag.StringVar(&meetingFolder, "meeting-folder", "", "where to store the meeting notes")
@@ -34,7 +49,27 @@ func init() {
 	flag.BoolVar(&printConfig, "print-config", false, "print configuration")
 	flag.BoolVar(&dryRun, "dry-run", false, "pass to not create files")
 	flag.BoolVar(&force, "force", false, "pass to overwrite existing files")
-	flag.StringVar(&icalFile, "ical-file", "", "pass ical file")
+	flag.StringVar(&icalFile, "ical-file", "", "comma separated list of ical files or directories, - for stdin (path;folder=sub;tag=name)")
+	flag.StringVar(&stdinCalendar, "stdin-calendar", "", "calendar name of events read from stdin if the calendar does not name itself")
+	flag.StringVar(&templateFile, "template-file", "", "path to template file for meeting notes")
+	flag.BoolVar(&attendeeLinks, "attendee-links", false, "pass to write attendees as links to person notes")
+	flag.StringVar(&timezone, "timezone", "", "timezone to write meeting times in, e.g. Europe/Berlin (default: local)")
+	flag.StringVar(&fromDate, "from", "", "first day of the time window for events (2006-01-02 or +-offset, default: now)")
+	flag.StringVar(&toDate, "to", "+90", "last day of the time window for events (2006-01-02 or +-offset)")
+	flag.BoolVar(&series, "series", false, "pass to create one note per recurring event listing its occurrences")
+	flag.StringVar(&includeTitle, "include", "", "regular expression, only events with a matching title become notes")
+	flag.StringVar(&excludeTitle, "exclude", "", "regular expression, events with a matching title are skipped")
+	flag.BoolVar(&skipAllDay, "skip-all-day", false, "pass to skip all-day events")
+	flag.BoolVar(&skipDeclined, "skip-declined", false, "pass to skip events declined by one of the -my-email addresses")
+	myEmails = flag.StringSliceVar("my-email", []string{}, "comma separated list of own email addresses")
+	flag.IntVar(&minAttendees, "min-attendees", 0, "skip events with fewer attendees")
+	categories = flag.StringSliceVar("category", []string{}, "comma separated list of categories, only events with one of them become notes")
+	excludeCategories = flag.StringSliceVar("exclude-category", []string{}, "comma separated list of categories to skip events for")
+	calendars = flag.StringSliceVar("calendar", []string{}, "comma separated list of calendar names (X-WR-CALNAME) to import")
+	flag.BoolVar(&linkDailyNote, "link-daily", false, "pass to link meeting notes in the daily note of their day")
+	flag.StringVar(&dailyFolder, "daily-folder", "", "where the daily notes are stored inside the vault")
+	flag.StringVar(&dailyHeadline, "daily-headline", meeting.DefaultDailyHeadline, "headline in the daily note to link meetings below")
+	flag.BoolVar(&syncNotes, "sync", false, "pass to update existing notes found by event uid instead of creating new ones")
 }
 
 func main() {
@@ -47,31 +82,22 @@ func main() {
 	}
 }
 
-// run parses an iCal file, creates meeting notes in a specified folder, and supports options like dry-run and custom formatting.
+// run parses the iCal sources, creates meeting notes in a specified folder, and supports options like dry-run and custom formatting.
 func run(logger *slog.Logger) error {
 	logger.Info("start creating meeting notes from iCal file")
-	if icalFile == "" {
-		return errors.New("-ical-file must be non empty")
-	}
-	var (
-		f   *os.File
-		err error
-	)
-	if icalFile == "-" {
-		f = os.Stdin
-	} else {
-		f, err = os.OpenFile(icalFile, os.O_RDONLY, 0600)
+	sources, err := parseSources(icalFile)
+	if err != nil {
+		return err
 	}
+	loc, err := internal.LoadLocation(timezone)
 	if err != nil {
 		return err
 	}
-	c := gocal.NewParser(f)
-	c.SkipBounds = true
-	err = c.Parse()
+	events, err := readEvents(logger, sources, loc)
 	if err != nil {
 		return err
 	}
-	if len(c.Events) == 0 {
+	if len(events) == 0 {
 		logger.Info("no events found")
 		return nil
 	}
@@ -85,20 +111,93 @@ func run(logger *slog.Logger) error {
 	if err != nil {
 		return err
 	}
+	if linkDailyNote && dailyFolder == "" {
+		return errors.New("-daily-folder must be non empty to link meetings in daily notes")
+	}
+	var daily *meeting.DailyLinks
+	if linkDailyNote {
+		daily = &meeting.DailyLinks{Folder: path.Join(folder, dailyFolder), Headline: dailyHeadline, DryRun: dryRun, Logger: logger}
+	}
 	folder = path.Join(folder, meetingFolder)
+	from, to, err := internal.TimeWindow(time.Now(), fromDate, toDate, loc)
+	if err != nil {
+		return err
+	}
 
 	if printConfig {
-		fmt.Printf("meeting notes folder: %q", folder)
-		fmt.Printf("noDatePrefix: %t", noDatePrefix)
+		fmt.Printf("meeting notes folder: %q\n", folder)
+		for _, s := range sources {
+			fmt.Printf("source: %q folder=%q tag=%q\n", s.path, s.folder, s.tag)
+		}
+		fmt.Printf("noDatePrefix: %t\n", noDatePrefix)
+		fmt.Printf("sync: %t\n", syncNotes)
+		fmt.Printf("series: %t\n", series)
+		fmt.Printf("timezone: %q\n", loc)
+		fmt.Printf("link daily: %t (%q below %q)\n", linkDailyNote, dailyFolder, dailyHeadline)
+		fmt.Printf("time window: %s - %s\n", from.Format(time.DateTime), to.Format(time.DateTime))
 		return nil
 	}
 
-	for _, event := range c.Events {
-		if event.Start.Before(time.Now()) {
-			logger.Debug("skipping event in the past", "summary", event.Summary, "start", *event.Start)
+	var index map[string]string
+	if syncNotes {
+		index, err = obsidianutils.IndexFrontmatter(folder, "uid")
+		if err != nil {
+			return err
+		}
+	}
+
+	filter, err := newEventFilter()
+	if err != nil {
+		return err
+	}
+	items, err := ical.Expand(logger, events, from, to, series, loc)
+	if err != nil {
+		return err
+	}
+	logger.Debug("events in time window", "count", len(items), "from", from, "to", to)
+
+	for _, it := range items {
+		it = it.In(loc)
+		event := it.Event
+		if reason := filter.Skip(event); reason != "" {
+			logger.Debug("skipping filtered event", "summary", event.Summary, "start", *event.Start, "reason", reason)
+			continue
+		}
+		src := eventSource(sources, event)
+		opts := eventOptions(event)
+		if src.tag != "" {
+			opts = append(opts, meeting.WithTags(src.tag))
+		}
+		if it.Rule != "" {
+			opts = append(opts, meeting.WithRecurrence(it.Rule), meeting.WithOccurrences(it.Occurrences...))
+		}
+		m, err := meeting.NewMeeting(opts...)
+		if err != nil {
+			return err
+		}
+		if fileName, ok := index[ical.Key(event)]; ok {
+			previous, err := syncNote(logger, fileName, m, event)
+			if err != nil {
+				return err
+			}
+			if !previous.IsZero() && !previous.Equal(*event.Start) {
+				// the link in the daily note of the previous day or time is stale
+				if err := daily.Unlink(fileName, previous.In(loc)); err != nil {
+					return err
+				}
+			}
+			if !ical.IsCancelled(event) {
+				if err := daily.Link(fileName, *event.Start); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if ical.IsCancelled(event) {
+			logger.Debug("skipping cancelled event", "summary", event.Summary, "start", *event.Start)
 			continue
 		}
-		fullName, err := obsidianutils.CreateFileName(folder, event.Summary, noDatePrefix, *event.Start)
+		fullName, err := obsidianutils.CreateFileName(path.Join(folder, src.folder), event.Summary, noDatePrefix, *event.Start)
 		if err != nil {
 			return err
 		}
@@ -118,21 +217,61 @@ func run(logger *slog.Logger) error {
 		}
 		if dryRun {
 			fmt.Printf("would create meeting with [%s] on [%s] in [%s]\n", event.Summary, *event.Start, fullName)
+			if err := daily.Link(fullName, *event.Start); err != nil {
+				return err
+			}
 			continue
 		}
-		m, err := meeting.NewMeeting(meeting.WithTitle(event.Summary))
+		c, err := m.CreateContent(event.Summary, *event.Start)
 		if err != nil {
 			return err
 		}
-		c, err := m.CreateContent(event.Summary, *event.Start)
-		if err != nil {
+		if err := os.MkdirAll(path.Dir(fullName), 0700); err != nil {
 			return err
 		}
 
 		if err = os.WriteFile(fullName, []byte(c), 0600); err != nil {
 			return err
 		}
+		if index != nil {
+			index[ical.Key(event)] = fullName
+		}
+		if err := daily.Link(fullName, *event.Start); err != nil {
+			return err
+		}
 		logger.Info("created meeting", "summary", event.Summary, "start", *event.Start, "file", fullName)
 	}
 	return nil
 }
+
+// newLines replaces escaped line breaks, which gocal keeps when unescaping text values.
+var newLines = strings.NewReplacer(`\n`, "\n", `\N`, "\n")
+
+// eventOptions returns the options to create a meeting note from the event.
+func eventOptions(event gocal.Event) []meeting.OptionFunc {
+	opts := []meeting.OptionFunc{
+		meeting.WithTitle(event.Summary),
+		meeting.WithTemplate(templateFile),
+		meeting.WithUID(ical.Key(event)),
+		meeting.WithLocation(event.Location),
+		meeting.WithDescription(strings.TrimSpace(newLines.Replace(event.Description))),
+		meeting.WithAttendeeLinks(attendeeLinks),
+	}
+	if event.End != nil {
+		opts = append(opts, meeting.WithEnd(*event.End))
+	}
+	if event.Organizer != nil && (event.Organizer.Cn != "" || event.Organizer.Value != "") {
+		opts = append(opts, meeting.WithOrganizer(meeting.Attendee{
+			Name:  event.Organizer.Cn,
+			Email: ical.MailAddress(event.Organizer.Value),
+		}))
+	}
+	for _, a := range event.Attendees {
+		opts = append(opts, meeting.WithAttendees(meeting.Attendee{
+			Name:   a.Cn,
+			Email:  ical.MailAddress(a.Value),
+			Status: a.Status,
+		}))
+	}
+	return opts
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/apognu/gocal"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
+	"github.com/sascha-andres/obsidian-utils/internal/ical"
+)
+
+// source is a calendar to import, either a file, a directory of .ics files like a vdir or stdin ("-").
+type source struct {
+	// path is the file or directory to read.
+	path string
+
+	// folder is the subfolder of the meeting folder to create notes in.
+	folder string
+
+	// tag is added to the tags of the notes created for the events of the source.
+	tag string
+}
+
+// parseSources reads the comma separated list of sources passed as -ical-file. Each source is a path optionally
+// followed by options separated by semicolons, e.g. work.ics;folder=Work;tag=work.
+func parseSources(value string) ([]source, error) {
+	var result []source
+	for _, spec := range strings.Split(value, ",") {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		parts := strings.Split(spec, ";")
+		p, err := obsidianutils.ApplyDirectoryPlaceHolder(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, err
+		}
+		s := source{path: p}
+		for _, option := range parts[1:] {
+			key, val, _ := strings.Cut(option, "=")
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "folder":
+				s.folder = strings.TrimSpace(val)
+			case "tag":
+				s.tag = strings.TrimPrefix(strings.TrimSpace(val), "#")
+			default:
+				return nil, fmt.Errorf("unknown option %q for source %q", key, s.path)
+			}
+		}
+		result = append(result, s)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("-ical-file must be non empty")
+	}
+	return result, nil
+}
+
+// files returns the calendar files of the source.
+func (s source) files() ([]string, error) {
+	if s.path == "-" {
+		return []string{s.path}, nil
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{s.path}, nil
+	}
+	var result []string
+	err = filepath.WalkDir(s.path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".ics") {
+			result = append(result, p)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// readEvents reads the events of all sources. The index of the source is stored in each event. Events found in
+// several sources are merged, see ical.Deduplicate. Calendars not naming themselves are named after the vdir
+// displayname file, or -stdin-calendar for stdin.
+func readEvents(logger *slog.Logger, sources []source, loc *time.Location) ([]gocal.Event, error) {
+	var result []gocal.Event
+	for i, s := range sources {
+		files, err := s.files()
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			fallback := stdinCalendar
+			if file != "-" {
+				fallback = displayName(filepath.Dir(file))
+			}
+			events, err := readCalendarFile(file, fallback, loc)
+			if err != nil {
+				return nil, fmt.Errorf("could not read calendar %s: %w", file, err)
+			}
+			logger.Debug("read calendar", "file", file, "events", len(events))
+			for _, event := range events {
+				ical.SetSource(event, i)
+			}
+			result = append(result, events...)
+		}
+	}
+	merged := ical.Deduplicate(result)
+	if len(merged) < len(result) {
+		logger.Debug("merged duplicate events", "count", len(result)-len(merged))
+	}
+	return merged, nil
+}
+
+// readCalendarFile parses a single calendar file, "-" reads from stdin. The fallback is used as calendar name if the
+// file does not name its calendar. All-day events and times without timezone are read in loc.
+func readCalendarFile(file, fallback string, loc *time.Location) ([]gocal.Event, error) {
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return ical.Parse(r, fallback, loc)
+}
+
+// displayName returns the name vdirsyncer stores for the collection in the directory, empty if there is none.
+func displayName(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "displayname"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// eventSource returns the source the event was read from.
+func eventSource(sources []source, event gocal.Event) source {
+	i := ical.Source(event)
+	if i < 0 || i >= len(sources) {
+		return source{}
+	}
+	return sources[i]
+}
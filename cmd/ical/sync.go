@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/apognu/gocal"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
+	"github.com/sascha-andres/obsidian-utils/internal/ical"
+	"github.com/sascha-andres/obsidian-utils/internal/meeting"
+)
+
+// syncNote updates the frontmatter of an existing meeting note with the details of the event. The body of the note
+// is kept as is. Notes of cancelled events are flagged with cancelled: true. The start of the meeting stored in the
+// note before is returned, zero if the note has none.
+func syncNote(logger *slog.Logger, fileName string, m *meeting.Meeting, event gocal.Event) (time.Time, error) {
+	values, err := m.Frontmatter(*event.Start)
+	if err != nil {
+		return time.Time{}, err
+	}
+	fp := obsidianutils.NewSimpleFrontmatterProcessor(fileName)
+	var previous time.Time
+	if value, err := fp.GetValue("date"); err == nil {
+		previous = noteTime(value)
+	}
+	if ical.IsCancelled(event) {
+		values["cancelled"] = true
+	} else if _, err := fp.GetValue("cancelled"); err == nil {
+		values["cancelled"] = false
+	}
+
+	changed := false
+	for key, value := range values {
+		current, err := fp.GetValue(key)
+		if err == nil && fmt.Sprint(current) == fmt.Sprint(value) {
+			continue
+		}
+		if err := fp.SetValue(key, value); err != nil {
+			return previous, err
+		}
+		logger.Debug("changed frontmatter", "file", fileName, "key", key, "value", value)
+		changed = true
+	}
+	if !changed {
+		logger.Debug("meeting note is up to date", "file", fileName)
+		return previous, nil
+	}
+	if err := fp.SetValue("date modified", time.Now().Format(time.RFC850)); err != nil {
+		return previous, err
+	}
+	if dryRun {
+		fmt.Printf("would update meeting [%s] on [%s] in [%s]\n", event.Summary, *event.Start, fileName)
+		return previous, nil
+	}
+	data, err := fp.GenerateMarkDownDocument()
+	if err != nil {
+		return previous, err
+	}
+	if err := os.WriteFile(fileName, data, 0600); err != nil {
+		return previous, err
+	}
+	logger.Info("updated meeting", "summary", event.Summary, "start", *event.Start, "file", fileName)
+	return previous, nil
+}
+
+// noteTime returns the time stored in the frontmatter of a note, zero if it is no RFC 3339 time.
+func noteTime(value any) time.Time {
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}
+		}
+		return t
+	}
+	return time.Time{}
+}
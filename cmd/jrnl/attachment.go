@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
+	"github.com/sascha-andres/obsidian-utils/internal"
+)
+
+// appConfig contains the parts of .obsidian/app.json relevant for attachments.
+type appConfig struct {
+	// AttachmentFolderPath is "/" for the vault root, "./" for the folder of the note, "./sub" for a subfolder of
+	// the folder of the note or a path relative to the vault root.
+	AttachmentFolderPath string `json:"attachmentFolderPath"`
+}
+
+// resolveAttachmentFolder returns the folder new attachments are stored in. -attachment-folder takes precedence
+// over the setting in .obsidian/app.json, both are interpreted like Obsidian does relative to the vault and note.
+func resolveAttachmentFolder(vault, noteFile string) (string, error) {
+	setting := attachmentFolder
+	if setting == "" {
+		data, err := os.ReadFile(filepath.Join(vault, ".obsidian", "app.json"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		if err == nil {
+			var cfg appConfig
+			if err := json.Unmarshal(data, &cfg); err != nil {
+				return "", fmt.Errorf("could not read attachment folder from app.json: %w", err)
+			}
+			setting = cfg.AttachmentFolderPath
+		}
+	}
+	switch {
+	case setting == "" || setting == "/":
+		return vault, nil
+	case setting == "." || setting == "./":
+		return filepath.Dir(noteFile), nil
+	case strings.HasPrefix(setting, "./"):
+		return filepath.Join(filepath.Dir(noteFile), strings.TrimPrefix(setting, "./")), nil
+	}
+	return filepath.Join(vault, setting), nil
+}
+
+// uniqueFileName sanitises name and appends a counter if a file with that name already exists in folder or anywhere
+// else in the vault. Obsidian resolves ![[name]] vault-wide ignoring case, so the reference has to be unique there.
+func uniqueFileName(vault, folder, name string) (string, error) {
+	taken, err := vaultFileNames(vault)
+	if err != nil {
+		return "", err
+	}
+	name = obsidianutils.SanitizeFileName(name)
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 1; ; i++ {
+		exists, err := internal.Exists(filepath.Join(folder, candidate))
+		if err != nil {
+			return "", err
+		}
+		if !exists && !taken[strings.ToLower(candidate)] {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s %d%s", base, i, ext)
+	}
+}
+
+// vaultFileNames returns the lower case names of all files in the vault. Hidden folders like .obsidian are skipped
+// as Obsidian does not index them.
+func vaultFileNames(vault string) (map[string]bool, error) {
+	names := make(map[string]bool)
+	err := filepath.WalkDir(vault, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != vault && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		names[strings.ToLower(d.Name())] = true
+		return nil
+	})
+	return names, err
+}
+
+// readClipboardImage returns the PNG image currently in the clipboard using the platform's clipboard tool.
+func readClipboardImage() ([]byte, error) {
+	var cmd *exec.Cmd
+	switch {
+	case os.Getenv("WAYLAND_DISPLAY") != "":
+		cmd = exec.Command("wl-paste", "--type", "image/png")
+	case os.Getenv("DISPLAY") != "":
+		cmd = exec.Command("xclip", "-selection", "clipboard", "-target", "image/png", "-out")
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("pngpaste", "-")
+	default:
+		return nil, fmt.Errorf("reading the clipboard is not supported on this platform")
+	}
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not read image from clipboard using %s: %w", cmd.Path, err)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("clipboard does not contain an image")
+	}
+	return data, nil
+}
+
+// attachment is a captured file to store in the attachment folder of the vault.
+type attachment struct {
+	// file is the path the attachment is stored at.
+	file string
+	// data is the content of the attachment.
+	data []byte
+}
+
+// store writes the attachment, creating the attachment folder if required.
+func (a attachment) store() error {
+	if err := os.MkdirAll(filepath.Dir(a.file), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(a.file, a.data, 0600)
+}
+
+// captureAttachment reads the file passed with -attach or the clipboard image and returns it together with the embed
+// or link to insert into the note. The attachment is stored using store once the entry was added to the note, so
+// no attachment is left behind if that fails.
+func captureAttachment(vault, noteFile string, now time.Time) (attachment, string, error) {
+	linkFormat := ""
+	switch attachAs {
+	case "embed":
+		linkFormat = "![[%s]]"
+	case "link":
+		linkFormat = "[[%s]]"
+	default:
+		return attachment{}, "", fmt.Errorf("unknown -attach-as %q (embed/link)", attachAs)
+	}
+	target, err := resolveAttachmentFolder(vault, noteFile)
+	if err != nil {
+		return attachment{}, "", err
+	}
+	var (
+		name string
+		data []byte
+	)
+	if attachClipboard {
+		name = fmt.Sprintf("Pasted image %s.png", now.Format("20060102150405"))
+		if data, err = readClipboardImage(); err != nil {
+			return attachment{}, "", err
+		}
+	} else {
+		name = filepath.Base(attach)
+		if data, err = os.ReadFile(attach); err != nil {
+			return attachment{}, "", err
+		}
+	}
+	name, err = uniqueFileName(vault, target, name)
+	if err != nil {
+		return attachment{}, "", err
+	}
+	return attachment{file: filepath.Join(target, name), data: data}, fmt.Sprintf(linkFormat, name), nil
+}
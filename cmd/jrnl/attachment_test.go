@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeFiles creates the files with the given content below dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUniqueFileName(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		file  string
+		want  string
+	}{
+		{name: "Free name", file: "scan.png", want: "scan.png"},
+		{name: "Sanitised", file: "Rechnung März.pdf", want: "Rechnung Maerz.pdf"},
+		{name: "Taken in attachment folder", files: []string{"Attachments/scan.png"}, file: "scan.png", want: "scan 1.png"},
+		{name: "Taken elsewhere in vault", files: []string{"Projects/scan.png"}, file: "scan.png", want: "scan 1.png"},
+		{name: "Taken ignoring case", files: []string{"Projects/SCAN.PNG"}, file: "scan.png", want: "scan 1.png"},
+		{
+			name:  "Counter taken",
+			files: []string{"Attachments/scan.png", "Other/scan 1.png"},
+			file:  "scan.png",
+			want:  "scan 2.png",
+		},
+		{name: "Hidden folders are ignored", files: []string{".trash/scan.png"}, file: "scan.png", want: "scan.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := t.TempDir()
+			files := make(map[string]string)
+			for _, f := range tt.files {
+				files[f] = ""
+			}
+			writeFiles(t, vault, files)
+			got, err := uniqueFileName(vault, filepath.Join(vault, "Attachments"), tt.file)
+			if err != nil {
+				t.Fatalf("uniqueFileName() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("uniqueFileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptureAttachment(t *testing.T) {
+	tests := []struct {
+		name          string
+		files         map[string]string
+		folder        string
+		as            string
+		wantFile      string
+		wantReference string
+		wantErr       bool
+	}{
+		{
+			name:          "Embed in vault root",
+			as:            "embed",
+			wantFile:      "scan.png",
+			wantReference: "![[scan.png]]",
+		},
+		{
+			name:          "Link",
+			as:            "link",
+			wantFile:      "scan.png",
+			wantReference: "[[scan.png]]",
+		},
+		{
+			name:          "Folder from app.json",
+			files:         map[string]string{".obsidian/app.json": `{"attachmentFolderPath": "Attachments"}`},
+			as:            "embed",
+			wantFile:      "Attachments/scan.png",
+			wantReference: "![[scan.png]]",
+		},
+		{
+			name:          "Subfolder of the note",
+			folder:        "./files",
+			as:            "embed",
+			wantFile:      "Daily/files/scan.png",
+			wantReference: "![[scan.png]]",
+		},
+		{
+			name:          "Name taken elsewhere",
+			files:         map[string]string{"Projects/scan.png": "old"},
+			folder:        "Attachments",
+			as:            "embed",
+			wantFile:      "Attachments/scan 1.png",
+			wantReference: "![[scan 1.png]]",
+		},
+		{
+			name:    "Unknown reference",
+			as:      "inline",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := []string{attach, attachAs, attachmentFolder}
+			t.Cleanup(func() { attach, attachAs, attachmentFolder = saved[0], saved[1], saved[2] })
+			vault := t.TempDir()
+			writeFiles(t, vault, tt.files)
+			attach = filepath.Join(t.TempDir(), "scan.png")
+			writeFiles(t, filepath.Dir(attach), map[string]string{"scan.png": "image"})
+			attachAs, attachmentFolder = tt.as, tt.folder
+
+			captured, reference, err := captureAttachment(vault, filepath.Join(vault, "Daily", "2026-10-19.md"), time.Now())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("captureAttachment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if reference != tt.wantReference {
+				t.Errorf("captureAttachment() reference = %q, want %q", reference, tt.wantReference)
+			}
+			wantFile := filepath.Join(vault, tt.wantFile)
+			if captured.file != wantFile {
+				t.Fatalf("captureAttachment() file = %q, want %q", captured.file, wantFile)
+			}
+			if _, err := os.Stat(wantFile); err == nil {
+				t.Errorf("captureAttachment() stored %s before store was called", wantFile)
+			}
+			if err := captured.store(); err != nil {
+				t.Fatalf("store() error = %v", err)
+			}
+			data, err := os.ReadFile(wantFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "image" {
+				t.Errorf("store() wrote %q, want %q", data, "image")
+			}
+		})
+	}
+}
@@ -15,13 +15,25 @@ import (
 
 	obsidianutils "github.com/sascha-andres/obsidian-utils"
 	"github.com/sascha-andres/obsidian-utils/internal"
+	"github.com/sascha-andres/obsidian-utils/internal/journal"
+	"github.com/sascha-andres/obsidian-utils/internal/markdown"
 )
 
 var (
-	folder, forDate, dailyFolder string
-	headline                     = "## Other stuff"
-	logLevel                     string
-	dryRun                       bool
+	folder, forDate, dailyFolder        string
+	headline                            = "## Other stuff"
+	tasksHeadline                       = "## Tasks"
+	logLevel                            string
+	dryRun, task                        bool
+	due, scheduled, priority, tagPrefix string
+	taskFormat                          = "emoji"
+	tags                                func() []string
+	entryPrefix                         = journal.DefaultPrefix
+	timeFormat                          = "15:04"
+	timezone, at                        string
+	attach, attachmentFolder            string
+	attachAs                            = "embed"
+	attachClipboard                     bool
 )
 
 func init() {
@@ -30,9 +42,25 @@ func init() {
 	flag.StringVar(&logLevel, "log-level", "info", "log level")
 	flag.StringVar(&folder, "folder", "", "base path to obsidian vault")
 	flag.StringVar(&dailyFolder, "daily-folder", "", "where to store the daily note inside the vault")
-	flag.StringVar(&forDate, "for-date", time.Now().Format(time.DateOnly), "date for which to create the daily note (2006-01-02)")
+	flag.StringVar(&forDate, "for-date", "", "date of the daily note (2006-01-02 or +-offset, default: today in -timezone)")
 	flag.StringVar(&headline, "headline", headline, fmt.Sprintf("headline under which to place the journal note (default: %s)", headline))
 	flag.BoolVar(&dryRun, "dry-run", false, "pass to not edit file but to print added line with some context")
+	flag.BoolVar(&task, "task", false, "pass to add the entry as a task")
+	flag.StringVar(&tasksHeadline, "tasks-headline", tasksHeadline, "headline under which to place tasks")
+	flag.StringVar(&due, "due", "", "due date of the task (2006-01-02 or +-offset)")
+	flag.StringVar(&scheduled, "scheduled", "", "scheduled date of the task (2006-01-02 or +-offset)")
+	flag.StringVar(&priority, "priority", "", "priority of the task (lowest/low/medium/high/highest)")
+	flag.StringVar(&tagPrefix, "tag-prefix", "task/", "prefix for task tags not starting with #")
+	flag.StringVar(&taskFormat, "task-format", taskFormat, "syntax for task metadata (emoji/dataview)")
+	tags = flag.StringSliceVar("tag", []string{}, "comma separated list of tags for the task")
+	flag.StringVar(&entryPrefix, "entry-prefix", entryPrefix, "template for the prefix of journal entries (fields: .Time, .Date, .Weekday), empty for none")
+	flag.StringVar(&timeFormat, "time-format", timeFormat, "go time layout used for .Time in -entry-prefix, has to contain 15:04")
+	flag.StringVar(&timezone, "timezone", "", "timezone for the entry time, e.g. Europe/Berlin (default: local)")
+	flag.StringVar(&at, "at", "", "time of the entry (15:04), required for a time prefix when not writing to today's note")
+	flag.StringVar(&attach, "attach", "", "file to copy into the attachment folder and embed with the entry")
+	flag.BoolVar(&attachClipboard, "attach-clipboard", false, "pass to store the image in the clipboard as attachment")
+	flag.StringVar(&attachmentFolder, "attachment-folder", "", "attachment folder like in obsidian settings (default: from .obsidian/app.json)")
+	flag.StringVar(&attachAs, "attach-as", attachAs, "how to reference the attachment (embed/link)")
 }
 
 func main() {
@@ -50,7 +78,14 @@ func run(_ context.Context) error {
 	logger := internal.CreateLogger("OBS_UTIL_DAILY", logLevel)
 
 	logger.Debug("start adding a journal note")
-	dailyNoteFolder, err := constructFolder()
+	loc, err := internal.LoadLocation(timezone)
+	if err != nil {
+		return err
+	}
+	if err := journal.ValidateTimeFormat(timeFormat); err != nil {
+		return err
+	}
+	dailyNoteFolder, err := constructFolder(time.Now().In(loc))
 	if err != nil {
 		return err
 	}
@@ -60,7 +95,7 @@ func run(_ context.Context) error {
 		return err
 	}
 
-	resultingFile := path.Join(dailyNoteFolder, fmt.Sprintf("%s.md", t.Format("2006/01/2006-01-02")))
+	resultingFile := internal.DailyNotePath(dailyNoteFolder, t)
 	if e, _ := internal.Exists(resultingFile); !e {
 		return fmt.Errorf("file %s does not exist, consider to create with daily", resultingFile)
 	}
@@ -70,9 +105,10 @@ func run(_ context.Context) error {
 		return err
 	}
 
+	capture := attach != "" || attachClipboard
 	defaultEntry := strings.Join(flag.GetVerbs(), " ")
 	bulletPoint, err := internal.PromptText("Journal entry", defaultEntry, func(s string) error {
-		if len(s) == 0 {
+		if len(s) == 0 && !capture {
 			return errors.New("journal entry cannot be empty")
 		}
 		return nil
@@ -81,16 +117,54 @@ func run(_ context.Context) error {
 	if err != nil {
 		return err
 	}
+	var captured attachment
+	if capture {
+		vault, err := obsidianutils.ApplyDirectoryPlaceHolder(folder)
+		if err != nil {
+			return err
+		}
+		var reference string
+		captured, reference, err = captureAttachment(vault, resultingFile, time.Now())
+		if err != nil {
+			return err
+		}
+		bulletPoint = strings.TrimSpace(bulletPoint + " " + reference)
+	}
 	line := ""
-	if strings.HasPrefix(bulletPoint, "[ ]") {
-		line = fmt.Sprintf("%s", bulletPoint)
+	target := headline
+	var before func(string) bool
+	if isTaskEntry(bulletPoint) {
+		line, err = createTaskLine(bulletPoint, t)
+		if err != nil {
+			return err
+		}
+		target = tasksHeadline
 	} else {
-		line = fmt.Sprintf("(%s) %s", time.Now().Format("15:04"), bulletPoint)
+		entryTime, ok, err := journal.ResolveTime(t, at, loc, time.Now())
+		if err != nil {
+			return err
+		}
+		prefix, err := journal.RenderPrefix(entryPrefix, timeFormat, entryTime, ok)
+		if err != nil {
+			return err
+		}
+		line = prefix + bulletPoint
+		if ok {
+			before = markdown.LaterThan(entryTime)
+		} else {
+			logger.Info("no time for entry on another day, pass -at to add one", "for-date", forDate)
+		}
 	}
 
-	newFileData, err := addBulletpoint(fileData, line, headline)
+	newFileData, err := markdown.AddBulletpoint(fileData, line, target, before)
+	if errors.Is(err, markdown.ErrAnchorNotFound) && target != headline {
+		// daily notes created before the tasks headline was introduced do not have it
+		logger.Info("tasks headline not found, adding the task below the headline", "tasks-headline", target, "headline", headline)
+		target = headline
+		newFileData, err = markdown.AddBulletpoint(fileData, line, target, before)
+	}
 	if err != nil {
-		logger.Error("could not add bullet point", "err", err, "file", resultingFile, "headline", headline)
+		logger.Error("could not add bullet point", "err", err, "file", resultingFile, "headline", target)
 		return err
 	}
 
@@ -104,140 +178,24 @@ func run(_ context.Context) error {
 		return nil
 	}
 
-	return os.WriteFile(resultingFile, newFileData, 0640)
-}
-
-func addBulletpoint(data []byte, bulletPoint, after string) ([]byte, error) {
-	// Convert to lines for easier manipulation
-	content := string(data)
-	lines := strings.Split(content, "\n")
-
-	trimEq := func(a, b string) bool { return strings.TrimSpace(a) == strings.TrimSpace(b) }
-	isHeadline := func(s string) bool {
-		// A markdown headline starts with one or more '#'
-		s = strings.TrimSpace(s)
-		return strings.HasPrefix(s, "#")
-	}
-	isULItem := func(s string) (bool, string, rune) {
-		// Detect unordered list item of the form: optional spaces + ('-', '*', '+') + space
-		// Returns (ok, indent, marker)
-		if s == "" {
-			return false, "", 0
-		}
-		// Count leading spaces
-		i := 0
-		for i < len(s) && s[i] == ' ' {
-			i++
-		}
-		indent := s[:i]
-		rest := s[i:]
-		if len(rest) < 2 {
-			return false, "", 0
+	if capture {
+		if err := captured.store(); err != nil {
+			return err
 		}
-		switch rest[0] {
-		case '-', '*', '+':
-			if len(rest) >= 2 && rest[1] == ' ' {
-				return true, indent, rune(rest[0])
-			}
-		}
-		return false, "", 0
+		logger.Info("captured attachment", "file", captured.file)
 	}
-
-	// 1) Find the starting point line matching 'after'
-	start := -1
-	for i := range lines {
-		if trimEq(lines[i], after) {
-			start = i
-			break
-		}
-	}
-	if start == -1 {
-		return nil, fmt.Errorf("anchor line not found: %q", after)
-	}
-
-	// 2) Determine the scan window: from line after 'after' to before the next headline
-	end := len(lines)
-	for i := start + 1; i < len(lines); i++ {
-		if isHeadline(lines[i]) {
-			end = i
-			break
-		}
-	}
-
-	// 3) Search for the first unordered list in [start+1, end)
-	listStart := -1
-	listIndent := ""
-	listMarker := '-'
-	for i := start + 1; i < end; i++ {
-		if ok, indent, marker := isULItem(lines[i]); ok {
-			listStart = i
-			listIndent = indent
-			listMarker = marker
-			break
-		}
-	}
-
-	if listStart != -1 {
-		// 3a) Found a list. Find the last consecutive list item line to append after.
-		listEnd := listStart
-		for i := listStart; i < end; i++ {
-			if ok, _, _ := isULItem(lines[i]); ok {
-				listEnd = i
-				continue
-			}
-			break
-		}
-		// Insert a new list item after listEnd
-		insertion := listEnd + 1
-		newLine := fmt.Sprintf("%s%c %s", listIndent, listMarker, bulletPoint)
-		// Insert while preserving order
-		lines = append(lines[:insertion], append([]string{newLine}, lines[insertion:]...)...)
-	} else {
-		// 3b) No list found before next headline. Create a new list with the bullet point as first item.
-		// Requirement: ensure exactly one empty line between the starting line and the newly created list.
-		blankStart := start + 1
-		blankEnd := blankStart
-		// Consume existing blank lines right after the anchor (but stop at next headline boundary)
-		for blankEnd < len(lines) && blankEnd < end && strings.TrimSpace(lines[blankEnd]) == "" {
-			blankEnd++
-		}
-		// Ensure there is exactly one blank line between the anchor and the list:
-		// - If there were no blank lines, insert one at blankStart.
-		// - If there were multiple, collapse them to a single one by removing extras.
-		if blankStart >= len(lines) {
-			// Anchor was the last line; just append the one blank line and the new list item.
-			lines = append(lines, "", fmt.Sprintf("- %s", bulletPoint))
-		} else {
-			// We will make sure lines[blankStart] is a blank line and remove any additional blank lines up to blankEnd.
-			if strings.TrimSpace(lines[blankStart]) != "" {
-				// Insert a blank line at blankStart
-				lines = append(lines[:blankStart], append([]string{""}, lines[blankStart:]...)...)
-				// After insertion, the first non-blank shifts by +1
-				blankEnd = blankStart + 1
-				if blankEnd < len(lines) {
-					for blankEnd < len(lines) && blankEnd < end+1 && strings.TrimSpace(lines[blankEnd]) == "" {
-						blankEnd++
-					}
-				}
-			} else {
-				// Collapse multiple blank lines to exactly one
-				if blankEnd > blankStart+1 {
-					lines = append(lines[:blankStart+1], lines[blankEnd:]...)
-					// Adjust end boundary after deletion
-					end -= (blankEnd - (blankStart + 1))
-				}
-			}
-			// Insert the new list item right after the single blank line
-			insertion := blankStart + 1
-			lines = append(lines[:insertion], append([]string{fmt.Sprintf("- %s\n", bulletPoint)}, lines[insertion:]...)...)
+	if err := os.WriteFile(resultingFile, newFileData, 0640); err != nil {
+		if capture {
+			_ = os.Remove(captured.file)
 		}
+		return err
 	}
-
-	return []byte(strings.Join(lines, "\n")), nil
+	return nil
 }
 
-// constructFolder validates and processes folder paths, applies placeholders, and adjusts dates based on input parameters.
-func constructFolder() (string, error) {
+// constructFolder validates and processes folder paths, applies placeholders, and adjusts dates based on input
+// parameters. Offsets passed as -for-date are relative to now.
+func constructFolder(now time.Time) (string, error) {
 	if folder == "" {
 		return "", errors.New("-folder must be non empty")
 	}
@@ -249,7 +207,7 @@ func constructFolder() (string, error) {
 		return "", errors.New("-daily-folder must be non empty")
 	}
 	if forDate == "" {
-		forDate = time.Now().Format(time.DateOnly)
+		forDate = now.Format(time.DateOnly)
 	}
 	if strings.HasPrefix(forDate, "-") {
 		relativeString := strings.TrimPrefix(forDate, "-")
@@ -257,7 +215,7 @@ func constructFolder() (string, error) {
 		if err != nil {
 			return "", err
 		}
-		forDate = time.Now().AddDate(0, 0, offset*-1).Format(time.DateOnly)
+		forDate = now.AddDate(0, 0, offset*-1).Format(time.DateOnly)
 	}
 	if strings.HasPrefix(forDate, "+") {
 		relativeString := strings.TrimPrefix(forDate, "+")
@@ -265,7 +223,7 @@ func constructFolder() (string, error) {
 		if err != nil {
 			return "", err
 		}
-		forDate = time.Now().AddDate(0, 0, offset).Format(time.DateOnly)
+		forDate = now.AddDate(0, 0, offset).Format(time.DateOnly)
 	}
 
 	folder = path.Join(folder, dailyFolder)
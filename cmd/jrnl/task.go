@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sascha-andres/obsidian-utils/internal"
+	"github.com/sascha-andres/obsidian-utils/internal/tasks"
+)
+
+// isTaskEntry reports whether the entry should be written as a task. This is the case if -task was passed,
+// any task specific flag was set or the entry starts with an empty checkbox.
+func isTaskEntry(entry string) bool {
+	return task || due != "" || scheduled != "" || priority != "" || len(taskTags()) > 0 || strings.HasPrefix(entry, "[ ]")
+}
+
+// taskTags returns the non-empty tags passed using -tag.
+func taskTags() []string {
+	result := make([]string, 0)
+	for _, t := range tags() {
+		if strings.TrimSpace(t) != "" {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// createTaskLine builds a task line in the syntax of the Obsidian Tasks plugin from the entry and the task flags.
+// Offsets passed as -due or -scheduled are relative to day, the day of the daily note.
+func createTaskLine(entry string, day time.Time) (string, error) {
+	format, err := tasks.ParseFormat(taskFormat)
+	if err != nil {
+		return "", err
+	}
+	p, err := tasks.ParsePriority(priority)
+	if err != nil {
+		return "", err
+	}
+	t := tasks.Task{
+		Status:      tasks.StatusOpen,
+		Description: strings.TrimSpace(strings.TrimPrefix(entry, "[ ]")),
+		Priority:    p,
+	}
+	for _, tag := range taskTags() {
+		t.Tags = append(t.Tags, tasks.NormalizeTag(tag, tagPrefix))
+	}
+	if due != "" {
+		if t.Due, err = internal.ResolveDate(due, day); err != nil {
+			return "", err
+		}
+	}
+	if scheduled != "" {
+		if t.Scheduled, err = internal.ResolveDate(scheduled, day); err != nil {
+			return "", err
+		}
+	}
+	return t.Render(format)
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sascha-andres/reuse/flag"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
+	"github.com/sascha-andres/obsidian-utils/internal"
+	"github.com/sascha-andres/obsidian-utils/internal/tasks"
+)
+
+// openTask is an open task found in a daily note.
+type openTask struct {
+	// file is the path of the note relative to the daily notes folder, using forward slashes.
+	file string
+
+	// line is the 1-based line number of the task.
+	line int
+
+	// text is the raw line as found in the note.
+	text string
+
+	// task is the parsed task.
+	task tasks.Task
+}
+
+// id returns the identifier used to address the task on the command line.
+func (o openTask) id() string {
+	return fmt.Sprintf("%s:%d", o.file, o.line)
+}
+
+var (
+	folder, dailyFolder, logLevel, filePattern string
+	tag, dueBefore, due, date, match           string
+	tagPrefix                                  = "task/"
+	taskFormat                                 = "emoji"
+	printConfig, dryRun                        bool
+)
+
+// init initializes the package by setting up flag options, log flags, and prefix.
+func init() {
+	internal.AddCommonFlagPrefixes()
+	flag.SetEnvPrefix("OBS_UTIL_TASK")
+	flag.SetEnvPrefixForFlag("tag-prefix", "OBS_UTIL_JRNL")
+	flag.SetEnvPrefixForFlag("task-format", "OBS_UTIL_JRNL")
+	flag.StringVar(&logLevel, "log-level", "info", "pass log level (debug/info/warn/error)")
+	flag.StringVar(&folder, "folder", "", "base path to obsidian vault")
+	flag.StringVar(&dailyFolder, "daily-folder", "", "where the daily notes are stored inside the vault")
+	flag.BoolVar(&printConfig, "print-config", false, "print configuration")
+	flag.BoolVar(&dryRun, "dry-run", false, "pass to print the changed line instead of editing the note")
+	flag.StringVar(&tag, "tag", "", "list only tasks with this tag")
+	flag.StringVar(&tagPrefix, "tag-prefix", tagPrefix, "prefix for tags not starting with #")
+	flag.StringVar(&dueBefore, "due-before", "", "list only tasks due on or before this date (2006-01-02 or +-offset)")
+	flag.StringVar(&filePattern, "file", "", "list only tasks in notes matching this pattern, e.g. 2026/10/*")
+	flag.StringVar(&match, "match", "", "select the open task containing this text instead of passing an id")
+	flag.StringVar(&date, "date", "", "date recorded when completing or cancelling a task (2006-01-02 or +-offset)")
+	flag.StringVar(&due, "due", "", "new due date when rescheduling a task (2006-01-02 or +-offset)")
+	flag.StringVar(&taskFormat, "task-format", taskFormat, "syntax for task metadata if the task has none (emoji/dataview)")
+}
+
+// main is the entry point of the program.
+func main() {
+	flag.Parse()
+	internal.PrintFlags()
+	logger := internal.CreateLogger(logLevel, "OBS_UTIL_TASK")
+	if err := run(logger); err != nil {
+		logger.Error("error running task", "err", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command given as first verb: list (default), done, cancel or reschedule.
+func run(logger *slog.Logger) error {
+	if folder == "" {
+		return errors.New("-folder must be non empty")
+	}
+	folder, err := obsidianutils.ApplyDirectoryPlaceHolder(folder)
+	if err != nil {
+		return err
+	}
+	if dailyFolder == "" {
+		return errors.New("-daily-folder must be non empty")
+	}
+	notesFolder := path.Join(folder, dailyFolder)
+
+	if printConfig {
+		fmt.Printf("daily notes folder: %q\n", notesFolder)
+		return nil
+	}
+
+	verbs := flag.GetVerbs()
+	command := "list"
+	if len(verbs) > 0 {
+		command = verbs[0]
+	}
+	id := ""
+	if len(verbs) > 1 {
+		id = verbs[1]
+	}
+
+	switch command {
+	case "list":
+		return listTasks(logger, notesFolder)
+	case "done":
+		return changeTask(logger, notesFolder, id, func(line string) (string, error) {
+			return setStatus(line, tasks.StatusDone)
+		})
+	case "cancel":
+		return changeTask(logger, notesFolder, id, func(line string) (string, error) {
+			return setStatus(line, tasks.StatusCancelled)
+		})
+	case "reschedule":
+		if due == "" {
+			return errors.New("-due must be non empty to reschedule a task")
+		}
+		newDue, err := internal.ResolveDate(due, time.Now())
+		if err != nil {
+			return err
+		}
+		return changeTask(logger, notesFolder, id, func(line string) (string, error) {
+			return tasks.SetDue(line, newDue, lineFormat(line))
+		})
+	}
+	return fmt.Errorf("unknown command %q (list/done/cancel/reschedule)", command)
+}
+
+// setStatus completes or cancels the task in line using the date passed with -date or today.
+func setStatus(line string, status rune) (string, error) {
+	d, err := internal.ResolveDate(date, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return tasks.SetStatus(line, status, d, lineFormat(line))
+}
+
+// lineFormat returns the format a task line is written in, falling back to -task-format.
+func lineFormat(line string) tasks.Format {
+	fallback, err := tasks.ParseFormat(taskFormat)
+	if err != nil {
+		fallback = tasks.FormatEmoji
+	}
+	return tasks.DetectFormat(line, fallback)
+}
+
+// listTasks prints all open tasks matching the filters, ordered by due date.
+func listTasks(logger *slog.Logger, notesFolder string) error {
+	found, err := findOpenTasks(notesFolder)
+	if err != nil {
+		return err
+	}
+	var before time.Time
+	if dueBefore != "" {
+		if before, err = internal.ResolveDate(dueBefore, time.Now()); err != nil {
+			return err
+		}
+	}
+	result := make([]openTask, 0, len(found))
+	for _, t := range found {
+		if tag != "" && !t.task.HasTag(tasks.NormalizeTag(tag, tagPrefix)) {
+			continue
+		}
+		if !before.IsZero() && (t.task.Due.IsZero() || t.task.Due.After(before)) {
+			continue
+		}
+		result = append(result, t)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		di, dj := result[i].task.Due, result[j].task.Due
+		if di.IsZero() != dj.IsZero() {
+			return !di.IsZero()
+		}
+		return di.Before(dj)
+	})
+	logger.Debug("listing tasks", "found", len(found), "matching", len(result))
+	for _, t := range result {
+		fmt.Printf("%s\t%s\n", t.id(), strings.TrimSpace(t.text))
+	}
+	return nil
+}
+
+// findOpenTasks walks the daily notes folder and returns all open tasks of notes matching -file.
+func findOpenTasks(notesFolder string) ([]openTask, error) {
+	result := make([]openTask, 0)
+	err := filepath.WalkDir(notesFolder, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".md" {
+			return nil
+		}
+		rel, err := filepath.Rel(notesFolder, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if filePattern != "" {
+			if ok, err := path.Match(filePattern, rel); err != nil || !ok {
+				return err
+			}
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		for i, line := range strings.Split(string(data), "\n") {
+			t, ok := tasks.Parse(line)
+			if !ok || !t.IsOpen() {
+				continue
+			}
+			result = append(result, openTask{file: rel, line: i + 1, text: line, task: t})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// selectTask resolves the task to change, either by id (file:line) or by -match.
+func selectTask(notesFolder, id string) (openTask, error) {
+	if id == "" && match == "" {
+		return openTask{}, errors.New("pass a task id (file:line) or -match")
+	}
+	if id != "" {
+		idx := strings.LastIndex(id, ":")
+		if idx < 0 {
+			return openTask{}, fmt.Errorf("invalid task id %q, expected file:line", id)
+		}
+		line, err := strconv.Atoi(id[idx+1:])
+		if err != nil {
+			return openTask{}, fmt.Errorf("invalid line in task id %q: %w", id, err)
+		}
+		if !filepath.IsLocal(filepath.FromSlash(id[:idx])) {
+			return openTask{}, fmt.Errorf("invalid task id %q, the file must be inside %s", id, notesFolder)
+		}
+		return openTask{file: id[:idx], line: line}, nil
+	}
+	found, err := findOpenTasks(notesFolder)
+	if err != nil {
+		return openTask{}, err
+	}
+	candidates := make([]openTask, 0)
+	for _, t := range found {
+		if strings.Contains(t.text, match) {
+			candidates = append(candidates, t)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return openTask{}, fmt.Errorf("no open task contains %q", match)
+	case 1:
+		return candidates[0], nil
+	}
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.id())
+	}
+	return openTask{}, fmt.Errorf("%d open tasks contain %q, pass an id: %s", len(candidates), match, strings.Join(ids, ", "))
+}
+
+// changeTask applies change to the selected task. The note is re-read and the line is verified to still be an
+// open task (containing -match if passed) before it is replaced. All other lines are written back untouched.
+func changeTask(logger *slog.Logger, notesFolder, id string, change func(string) (string, error)) error {
+	selected, err := selectTask(notesFolder, id)
+	if err != nil {
+		return err
+	}
+	fileName := filepath.Join(notesFolder, filepath.FromSlash(selected.file))
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	if selected.line < 1 || selected.line > len(lines) {
+		return fmt.Errorf("%s has no line %d", selected.file, selected.line)
+	}
+	old := lines[selected.line-1]
+	if t, ok := tasks.Parse(old); !ok || !t.IsOpen() {
+		return fmt.Errorf("line %d of %s is not an open task: %q", selected.line, selected.file, strings.TrimSpace(old))
+	}
+	if match != "" && !strings.Contains(old, match) {
+		return fmt.Errorf("line %d of %s does not contain %q", selected.line, selected.file, match)
+	}
+	changed, err := change(old)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Printf("- %s\n+ %s\n", strings.TrimSpace(old), strings.TrimSpace(changed))
+		return nil
+	}
+	lines[selected.line-1] = changed
+	if err := writeFile(fileName, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return err
+	}
+	logger.Info("changed task", "file", fileName, "line", selected.line, "task", strings.TrimSpace(changed))
+	return nil
+}
+
+// writeFile replaces the file by writing to a temporary file in the same directory and renaming it afterwards,
+// so an interrupted write does not leave a truncated note behind.
+func writeFile(fileName string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(fileName), "."+filepath.Base(fileName)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fileName)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sascha-andres/obsidian-utils/internal/tasks"
+)
+
+// note is the daily note the tests change, line 5 is the open task "call Bob" and line 7 is done already.
+const note = `# 2026-10-17
+
+## Tasks
+
+- [ ] call Bob #task/work 📅 2026-10-20
+- [ ] call Alice
+- [x] book room ✅ 2026-10-16
+`
+
+func TestChangeTask(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	done := func(line string) (string, error) { return setStatus(line, tasks.StatusDone) }
+	cancel := func(line string) (string, error) { return setStatus(line, tasks.StatusCancelled) }
+	reschedule := func(line string) (string, error) {
+		return tasks.SetDue(line, time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC), lineFormat(line))
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		match   string
+		dryRun  bool
+		change  func(string) (string, error)
+		line    int
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "Done by id",
+			id:     "2026/10/2026-10-17.md:5",
+			change: done,
+			line:   5,
+			want:   "- [x] call Bob #task/work 📅 2026-10-20 ✅ 2026-10-19",
+		},
+		{
+			name:   "Cancel by match",
+			match:  "call Alice",
+			change: cancel,
+			line:   6,
+			want:   "- [-] call Alice ❌ 2026-10-19",
+		},
+		{
+			name:   "Reschedule by id and match",
+			id:     "2026/10/2026-10-17.md:5",
+			match:  "call Bob",
+			change: reschedule,
+			line:   5,
+			want:   "- [ ] call Bob #task/work 📅 2026-10-25",
+		},
+		{name: "Dry run", id: "2026/10/2026-10-17.md:5", dryRun: true, change: done},
+		{name: "Line out of range", id: "2026/10/2026-10-17.md:42", change: done, wantErr: true},
+		{name: "Line zero", id: "2026/10/2026-10-17.md:0", change: done, wantErr: true},
+		{name: "Line is no task", id: "2026/10/2026-10-17.md:3", change: done, wantErr: true},
+		{name: "Line is done", id: "2026/10/2026-10-17.md:7", change: done, wantErr: true},
+		{name: "Line does not match", id: "2026/10/2026-10-17.md:6", match: "call Bob", change: done, wantErr: true},
+		{name: "Ambiguous match", match: "call", change: done, wantErr: true},
+		{name: "No match", match: "walk the dog", change: done, wantErr: true},
+		{name: "Missing note", id: "2026/10/2026-10-18.md:5", change: done, wantErr: true},
+		{name: "Outside of the daily notes", id: "../2026-10-17.md:5", change: done, wantErr: true},
+		{name: "Invalid id", id: "2026-10-17.md", change: done, wantErr: true},
+		{name: "No id", change: done, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved, savedDate, savedDryRun := match, date, dryRun
+			t.Cleanup(func() { match, date, dryRun = saved, savedDate, savedDryRun })
+			match, date, dryRun = tt.match, "2026-10-19", tt.dryRun
+
+			notesFolder := t.TempDir()
+			fileName := filepath.Join(notesFolder, "2026", "10", "2026-10-17.md")
+			if err := os.MkdirAll(filepath.Dir(fileName), 0700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(fileName, []byte(note), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			err := changeTask(logger, notesFolder, tt.id, tt.change)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("changeTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			data, err := os.ReadFile(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := note
+			if tt.want != "" {
+				lines := strings.Split(note, "\n")
+				lines[tt.line-1] = tt.want
+				want = strings.Join(lines, "\n")
+			}
+			if diff := cmp.Diff(want, string(data)); diff != "" {
+				t.Errorf("changeTask() note mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
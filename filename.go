@@ -26,13 +26,18 @@ var replacements = map[string]string{
 // specified character replacements to the title and prefixes the file with the appointment date if the
 // noDatePrefix flag is not set. The generated file name is returned as a string.
 func CreateFileName(folder, localTitle string, noDatePrefix bool, timeForPrefix time.Time) (string, error) {
-	fixed := localTitle
-	for k, v := range replacements {
-		fixed = strings.ReplaceAll(fixed, k, v)
-	}
-	fName := fmt.Sprintf("%s.md", fixed)
+	fName := fmt.Sprintf("%s.md", SanitizeFileName(localTitle))
 	if !noDatePrefix {
 		fName = fmt.Sprintf("%s %s", timeForPrefix.Format("2006-01-02"), fName)
 	}
 	return ApplyDirectoryPlaceHolder(filepath.Join(folder, fName))
 }
+
+// SanitizeFileName applies the character replacements used for note file names to the given name, so it can be
+// used as file name and inside a wiki link.
+func SanitizeFileName(name string) string {
+	for k, v := range replacements {
+		name = strings.ReplaceAll(name, k, v)
+	}
+	return name
+}
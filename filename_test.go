@@ -104,3 +104,23 @@ func TestCreateFileName(t *testing.T) {
 		})
 	}
 }
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Umlauts", input: "Übersicht Größe.pdf", want: "Uebersicht Groesse.pdf"},
+		{name: "Link characters", input: "[Scan] 12:30/a.png", want: "Scan 1230-a.png"},
+		{name: "Unchanged", input: "Screenshot.png", want: "Screenshot.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeFileName(tt.input); got != tt.want {
+				t.Errorf("SanitizeFileName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,14 @@
 package obsidianutils
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"slices"
 
 	"github.com/adrg/frontmatter"
 	"gopkg.in/yaml.v2"
@@ -32,6 +38,8 @@ type SimpleFrontmatterProcessor struct {
 
 	// fm stores the frontmatter data.
 	fm map[string]any
+	// keys stores the order of the keys in the frontmatter of the file, it is kept when generating the document.
+	keys []string
 	// markDownData stores the markdown content.
 	markDownData []byte
 }
@@ -43,12 +51,13 @@ func NewSimpleFrontmatterProcessor(pathToNote string) *SimpleFrontmatterProcesso
 }
 
 // GenerateMarkDownDocument builds a complete Markdown document by combining frontmatter and markdown content.
+// Keys read from the file keep their order, added keys are appended sorted by name.
 // Returns the generated document as a byte slice or an error if no data is available or marshalling fails.
 func (sfp *SimpleFrontmatterProcessor) GenerateMarkDownDocument() ([]byte, error) {
 	if len(sfp.fm) == 0 && len(sfp.markDownData) == 0 {
 		return nil, errors.New("no markdown data loaded")
 	}
-	data, err := yaml.Marshal(sfp.fm)
+	data, err := yaml.Marshal(sfp.orderedFrontmatter())
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +90,63 @@ func (sfp *SimpleFrontmatterProcessor) SetValue(key string, value any) error {
 	return nil
 }
 
+// RemoveValue removes the key from the frontmatter metadata. Removing a missing key is not an error.
+func (sfp *SimpleFrontmatterProcessor) RemoveValue(key string) error {
+	if err := sfp.readDataIfRequired(); err != nil {
+		return err
+	}
+	delete(sfp.fm, key)
+	return nil
+}
+
+// IndexFrontmatter maps the values of the key found in the frontmatter of the notes in the folder and its subfolders
+// to the paths of the notes. Notes without the key or with a value that is not a string are left out, a missing
+// folder results in an empty index.
+func IndexFrontmatter(folder, key string) (map[string]string, error) {
+	index := make(map[string]string)
+	err := filepath.WalkDir(folder, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".md" {
+			return nil
+		}
+		value, err := NewSimpleFrontmatterProcessor(p).GetValue(key)
+		if err != nil {
+			return nil
+		}
+		if s, ok := value.(string); ok && s != "" {
+			index[s] = p
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return index, nil
+	}
+	return index, err
+}
+
+// orderedFrontmatter returns the frontmatter data with the keys in the order of the file followed by added keys.
+func (sfp *SimpleFrontmatterProcessor) orderedFrontmatter() yaml.MapSlice {
+	result := make(yaml.MapSlice, 0, len(sfp.fm))
+	for _, key := range sfp.keys {
+		if value, ok := sfp.fm[key]; ok {
+			result = append(result, yaml.MapItem{Key: key, Value: value})
+		}
+	}
+	added := make([]string, 0)
+	for key := range sfp.fm {
+		if !slices.Contains(sfp.keys, key) {
+			added = append(added, key)
+		}
+	}
+	slices.Sort(added)
+	for _, key := range added {
+		result = append(result, yaml.MapItem{Key: key, Value: sfp.fm[key]})
+	}
+	return result
+}
+
 // readDataIfRequired reads the frontmatter data and markdown content from the file if they have not already been read.
 func (sfp *SimpleFrontmatterProcessor) readDataIfRequired() error {
 	if len(sfp.fm) > 0 || len(sfp.markDownData) > 0 {
@@ -91,6 +157,24 @@ func (sfp *SimpleFrontmatterProcessor) readDataIfRequired() error {
 	if err != nil {
 		return err
 	}
-	sfp.markDownData, err = frontmatter.Parse(f, &sfp.fm)
-	return err
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	sfp.markDownData, err = frontmatter.Parse(bytes.NewReader(data), &sfp.fm)
+	if sfp.fm == nil {
+		sfp.fm = make(map[string]any)
+	}
+	if err != nil {
+		return err
+	}
+	// the order is only known for YAML frontmatter, other formats get their keys sorted
+	var ordered yaml.MapSlice
+	if _, err := frontmatter.Parse(bytes.NewReader(data), &ordered); err == nil {
+		for _, item := range ordered {
+			sfp.keys = append(sfp.keys, fmt.Sprint(item.Key))
+		}
+	}
+	return nil
 }
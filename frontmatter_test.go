@@ -0,0 +1,125 @@
+package obsidianutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerateMarkDownDocument(t *testing.T) {
+	tests := []struct {
+		name   string
+		note   string
+		set    map[string]any
+		remove []string
+		want   string
+	}{
+		{
+			name: "Keeps the order of the keys",
+			note: "---\ntitle: Weekly\nuid: abc\nattendees:\n- Bob\n---\n\n# Weekly\n",
+			want: "---\ntitle: Weekly\nuid: abc\nattendees:\n- Bob\n---\n\n# Weekly\n",
+		},
+		{
+			name: "Changes values in place",
+			note: "---\ntitle: Weekly\nuid: abc\nlocation: Room 1\n---\n\n# Weekly\n",
+			set:  map[string]any{"uid": "def"},
+			want: "---\ntitle: Weekly\nuid: def\nlocation: Room 1\n---\n\n# Weekly\n",
+		},
+		{
+			name: "Appends added keys sorted by name",
+			note: "---\ntitle: Weekly\n---\n\n# Weekly\n",
+			set:  map[string]any{"uid": "abc", "cancelled": true},
+			want: "---\ntitle: Weekly\ncancelled: true\nuid: abc\n---\n\n# Weekly\n",
+		},
+		{
+			name:   "Removes keys",
+			note:   "---\ntitle: Weekly\nuid: abc\nlocation: Room 1\n---\n\n# Weekly\n",
+			remove: []string{"uid"},
+			want:   "---\ntitle: Weekly\nlocation: Room 1\n---\n\n# Weekly\n",
+		},
+		{
+			name: "Adds frontmatter to a note without",
+			note: "# Weekly\n",
+			set:  map[string]any{"uid": "abc"},
+			want: "---\nuid: abc\n---\n# Weekly\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := filepath.Join(t.TempDir(), "note.md")
+			if err := os.WriteFile(note, []byte(tt.note), 0600); err != nil {
+				t.Fatal(err)
+			}
+			fp := NewSimpleFrontmatterProcessor(note)
+			_, _ = fp.GetValue("title")
+			for key, value := range tt.set {
+				if err := fp.SetValue(key, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, key := range tt.remove {
+				if err := fp.RemoveValue(key); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := fp.GenerateMarkDownDocument()
+			if err != nil {
+				t.Fatalf("GenerateMarkDownDocument() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("GenerateMarkDownDocument() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexFrontmatter(t *testing.T) {
+	dir := t.TempDir()
+	notes := map[string]string{
+		"Weekly.md":         "---\nuid: abc\n---\n\n# Weekly\n",
+		"Work/Review.md":    "---\nuid: def\n---\n\n# Review\n",
+		"Empty.md":          "---\nuid: \"\"\n---\n",
+		"Number.md":         "---\nuid: 42\n---\n",
+		"No frontmatter.md": "# Nothing\n",
+		"Other.txt":         "---\nuid: ghi\n---\n",
+	}
+	for name, content := range notes {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		folder string
+		want   map[string]string
+	}{
+		{
+			name:   "Indexes notes in subfolders",
+			folder: dir,
+			want:   map[string]string{"abc": filepath.Join(dir, "Weekly.md"), "def": filepath.Join(dir, "Work", "Review.md")},
+		},
+		{
+			name:   "Missing folder",
+			folder: filepath.Join(dir, "missing"),
+			want:   map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IndexFrontmatter(tt.folder, "uid")
+			if err != nil {
+				t.Fatalf("IndexFrontmatter() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("IndexFrontmatter() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/sascha-andres/obsidian-utils/internal/google"
+)
+
+// Primary is the id of the primary calendar of the authenticated user.
+const Primary = "primary"
+
+// maxResults is the largest page size the Calendar API accepts for events.
+const maxResults = 2500
+
+// Client fetches calendars and events from the Google Calendar API, following all pages and retrying requests that
+// failed because of rate limits or server errors.
+type Client struct {
+	srv        *calendar.Service
+	maxResults int64
+	retries    int
+	backoff    time.Duration
+}
+
+// OptionFunc defines a function type that modifies a Client instance or returns an error.
+type OptionFunc func(c *Client) error
+
+// WithPageSize sets the number of events or calendars requested per page, at most 2500.
+func WithPageSize(size int) OptionFunc {
+	return func(c *Client) error {
+		if size < 1 || size > maxResults {
+			return fmt.Errorf("page size must be between 1 and %d", maxResults)
+		}
+		c.maxResults = int64(size)
+		return nil
+	}
+}
+
+// WithRetries sets how often a request is retried and the wait before the first retry, which doubles with every
+// further retry. A Retry-After header sent by the API takes precedence.
+func WithRetries(retries int, backoff time.Duration) OptionFunc {
+	return func(c *Client) error {
+		if retries < 0 {
+			return fmt.Errorf("invalid number of retries %d", retries)
+		}
+		c.retries = retries
+		c.backoff = backoff
+		return nil
+	}
+}
+
+// NewClient initializes and returns a new Client using the Calendar service with the provided options.
+func NewClient(srv *calendar.Service, opts ...OptionFunc) (*Client, error) {
+	c := &Client{srv: srv, maxResults: maxResults, retries: 5, backoff: time.Second}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Calendars returns the calendars in the calendar list of the authenticated user.
+func (c *Client) Calendars(ctx context.Context) ([]*calendar.CalendarListEntry, error) {
+	var (
+		result    []*calendar.CalendarListEntry
+		pageToken string
+	)
+	for {
+		var r *calendar.CalendarList
+		err := google.Retry(ctx, c.retries, c.backoff, func() (err error) {
+			r, err = c.srv.CalendarList.List().
+				MaxResults(min(c.maxResults, 250)).
+				PageToken(pageToken).
+				Context(ctx).
+				Do()
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve calendars: %w", err)
+		}
+		result = append(result, r.Items...)
+		if r.NextPageToken == "" {
+			return result, nil
+		}
+		pageToken = r.NextPageToken
+	}
+}
+
+// SelectCalendars returns the calendars of the calendar list named by their id or their name, ignoring case.
+// "primary" selects the primary calendar. An error is returned for names matching no calendar.
+func (c *Client) SelectCalendars(ctx context.Context, names []string) ([]*calendar.CalendarListEntry, error) {
+	calendars, err := c.Calendars(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []*calendar.CalendarListEntry
+	for _, name := range names {
+		found := false
+		for _, cal := range calendars {
+			if cal.Id == name || strings.EqualFold(cal.Summary, name) || strings.EqualFold(cal.SummaryOverride, name) ||
+				(name == Primary && cal.Primary) {
+				result = append(result, cal)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no calendar %q", name)
+		}
+	}
+	return result, nil
+}
+
+// Events returns the events of the calendar starting before to and ending after from, ordered by their start.
+// Recurring events are expanded into their occurrences, cancelled events are left out.
+func (c *Client) Events(ctx context.Context, calendarID string, from, to time.Time) ([]*calendar.Event, error) {
+	var (
+		result    []*calendar.Event
+		pageToken string
+	)
+	for {
+		var r *calendar.Events
+		err := google.Retry(ctx, c.retries, c.backoff, func() (err error) {
+			r, err = c.srv.Events.List(calendarID).
+				TimeMin(from.Format(time.RFC3339)).
+				TimeMax(to.Format(time.RFC3339)).
+				SingleEvents(true).
+				OrderBy("startTime").
+				MaxResults(c.maxResults).
+				PageToken(pageToken).
+				Context(ctx).
+				Do()
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve events of %s: %w", calendarID, err)
+		}
+		result = append(result, r.Items...)
+		if r.NextPageToken == "" {
+			return result, nil
+		}
+		pageToken = r.NextPageToken
+	}
+}
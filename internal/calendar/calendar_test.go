@@ -0,0 +1,214 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/sascha-andres/obsidian-utils/internal/google/googletest"
+)
+
+// fakeCalendarAPI serves the calendar list and the events of the calendars in pages of two like the Calendar API.
+// Only events overlapping timeMin and timeMax are returned. The first failures requests are answered with 429 Too Many
+// Requests.
+type fakeCalendarAPI struct {
+	t         *testing.T
+	calendars []*calendar.CalendarListEntry
+	events    map[string][]*calendar.Event
+	failures  int
+	requests  int
+}
+
+func (f *fakeCalendarAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests++
+	if f.failures > 0 {
+		f.failures--
+		googletest.Error(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return
+	}
+	query := r.URL.Query()
+	calendarID, isEvents := strings.CutPrefix(strings.TrimSuffix(r.URL.Path, "/events"), "/calendars/")
+	var response any
+	switch {
+	case r.URL.Path == "/users/me/calendarList":
+		items, next := googletest.Page(f.calendars, query.Get("pageToken"))
+		response = calendar.CalendarList{Items: items, NextPageToken: next}
+	case isEvents:
+		if query.Get("singleEvents") != "true" || query.Get("orderBy") != "startTime" {
+			f.t.Errorf("events requested with singleEvents=%q orderBy=%q", query.Get("singleEvents"), query.Get("orderBy"))
+		}
+		timeMin, errMin := time.Parse(time.RFC3339, query.Get("timeMin"))
+		timeMax, errMax := time.Parse(time.RFC3339, query.Get("timeMax"))
+		if errMin != nil || errMax != nil {
+			f.t.Errorf("invalid time window %q - %q", query.Get("timeMin"), query.Get("timeMax"))
+		}
+		var events []*calendar.Event
+		for _, e := range f.events[calendarID] {
+			start, _ := Start(e, time.UTC)
+			end, _ := End(e, time.UTC)
+			if start.Before(timeMax) && end.After(timeMin) {
+				events = append(events, e)
+			}
+		}
+		items, next := googletest.Page(events, query.Get("pageToken"))
+		response = calendar.Events{Items: items, NextPageToken: next}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// newTestService returns a Calendar service using the fake API.
+func newTestService(t *testing.T, api *fakeCalendarAPI) *calendar.Service {
+	api.t = t
+	srv, err := calendar.NewService(context.Background(), googletest.Options(t, api)...)
+	if err != nil {
+		t.Fatalf("calendar.NewService() error = %v", err)
+	}
+	return srv
+}
+
+// timed returns an event from start lasting an hour.
+func timed(id string, start time.Time) *calendar.Event {
+	return &calendar.Event{
+		Id:      id,
+		Summary: id,
+		Start:   &calendar.EventDateTime{DateTime: start.Format(time.RFC3339)},
+		End:     &calendar.EventDateTime{DateTime: start.Add(time.Hour).Format(time.RFC3339)},
+	}
+}
+
+func TestEvents(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	api := &fakeCalendarAPI{events: map[string][]*calendar.Event{
+		"primary": {
+			timed("before", day.Add(-2*time.Hour)),
+			timed("first", day.Add(9*time.Hour)),
+			timed("second", day.Add(10*time.Hour)),
+			{Id: "all-day", Start: &calendar.EventDateTime{Date: "2026-03-02"}, End: &calendar.EventDateTime{Date: "2026-03-03"}},
+			timed("third", day.Add(11*time.Hour)),
+			timed("after", day.Add(30*time.Hour)),
+		},
+	}}
+	tests := []struct {
+		name         string
+		calendarID   string
+		failures     int
+		retries      int
+		want         string
+		wantErr      bool
+		wantRequests int
+	}{
+		{
+			name:         "All pages in time window",
+			calendarID:   "primary",
+			want:         "[first second all-day third]",
+			wantRequests: 2,
+		},
+		{
+			name:         "Retry on rate limit",
+			calendarID:   "primary",
+			failures:     2,
+			retries:      2,
+			want:         "[first second all-day third]",
+			wantRequests: 4,
+		},
+		{
+			name:         "Retries used up",
+			calendarID:   "primary",
+			failures:     3,
+			retries:      2,
+			wantErr:      true,
+			wantRequests: 3,
+		},
+		{
+			name:         "Empty calendar",
+			calendarID:   "other",
+			want:         "[]",
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api.requests, api.failures = 0, tt.failures
+			c, err := NewClient(newTestService(t, api), WithPageSize(2), WithRetries(tt.retries, time.Millisecond))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			events, err := c.Events(context.Background(), tt.calendarID, day, day.AddDate(0, 0, 1))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Events() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if api.requests != tt.wantRequests {
+				t.Errorf("Events() made %d requests, want %d", api.requests, tt.wantRequests)
+			}
+			if tt.wantErr {
+				return
+			}
+			var ids []string
+			for _, e := range events {
+				ids = append(ids, e.Id)
+			}
+			if got := fmt.Sprint(ids); got != tt.want {
+				t.Errorf("Events() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectCalendars(t *testing.T) {
+	api := &fakeCalendarAPI{calendars: []*calendar.CalendarListEntry{
+		{Id: "me@example.com", Summary: "me@example.com", Primary: true},
+		{Id: "team@group.calendar.google.com", Summary: "Team"},
+		{Id: "holidays@group.calendar.google.com", Summary: "Holidays"},
+	}}
+	tests := []struct {
+		name    string
+		names   []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "By name, id and primary",
+			names: []string{"team", "holidays@group.calendar.google.com", "primary"},
+			want:  "[team@group.calendar.google.com holidays@group.calendar.google.com me@example.com]",
+		},
+		{
+			name:    "Unknown calendar",
+			names:   []string{"Private"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(newTestService(t, api), WithPageSize(2))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			calendars, err := c.SelectCalendars(context.Background(), tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectCalendars() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var ids []string
+			for _, cal := range calendars {
+				ids = append(ids, cal.Id)
+			}
+			if got := fmt.Sprint(ids); got != tt.want {
+				t.Errorf("SelectCalendars() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
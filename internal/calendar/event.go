@@ -0,0 +1,132 @@
+package calendar
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/sascha-andres/obsidian-utils/internal/meeting"
+)
+
+// responseStatus maps the response status of the Calendar API to the participation status used in iCal files.
+var responseStatus = map[string]string{
+	"accepted":    "ACCEPTED",
+	"declined":    "DECLINED",
+	"tentative":   "TENTATIVE",
+	"needsAction": "NEEDS-ACTION",
+}
+
+// Start returns the start of the event in loc, midnight for all-day events.
+func Start(e *calendar.Event, loc *time.Location) (time.Time, error) {
+	return eventTime(e.Start, loc)
+}
+
+// End returns the end of the event in loc, midnight after the last day for all-day events.
+func End(e *calendar.Event, loc *time.Location) (time.Time, error) {
+	return eventTime(e.End, loc)
+}
+
+// IsAllDay reports whether the event lasts whole days.
+func IsAllDay(e *calendar.Event) bool {
+	return e.Start != nil && e.Start.DateTime == "" && e.Start.Date != ""
+}
+
+// IsCancelled reports whether the event was cancelled by the organizer.
+func IsCancelled(e *calendar.Event) bool {
+	return e.Status == "cancelled"
+}
+
+// Key returns the key the note of the event is found by, the iCal uid like for notes written by ical. Occurrences of
+// recurring events get their original start appended like its RECURRENCE-ID, as they share the uid, so ical and ggl
+// find the notes of each other.
+func Key(e *calendar.Event) string {
+	uid := e.ICalUID
+	if uid == "" {
+		uid = e.Id
+	}
+	if e.RecurringEventId == "" || e.OriginalStartTime == nil {
+		return uid
+	}
+	return uid + "#" + recurrenceID(e.OriginalStartTime)
+}
+
+// recurrenceID formats the original start of an occurrence like the RECURRENCE-ID of the iCal export: dates as
+// 20060102, times with timezone in it as 20060102T150405 and other times in UTC as 20060102T150405Z.
+func recurrenceID(t *calendar.EventDateTime) string {
+	if t.DateTime == "" {
+		date, err := time.Parse(time.DateOnly, t.Date)
+		if err != nil {
+			return t.Date
+		}
+		return date.Format("20060102")
+	}
+	start, err := time.Parse(time.RFC3339, t.DateTime)
+	if err != nil {
+		return t.DateTime
+	}
+	if t.TimeZone != "" {
+		if loc, err := time.LoadLocation(t.TimeZone); err == nil {
+			return start.In(loc).Format("20060102T150405")
+		}
+	}
+	return start.UTC().Format("20060102T150405Z")
+}
+
+// Options returns the options to create a meeting note from the event in loc. Attendees that are resources like
+// rooms are left out, attendees without name are named after their email address.
+func Options(e *calendar.Event, loc *time.Location) ([]meeting.OptionFunc, error) {
+	opts := []meeting.OptionFunc{
+		meeting.WithTitle(e.Summary),
+		meeting.WithUID(Key(e)),
+		meeting.WithLocation(e.Location),
+		meeting.WithDescription(strings.TrimSpace(e.Description)),
+	}
+	if e.End != nil {
+		end, err := End(e, loc)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, meeting.WithEnd(end))
+	}
+	if e.Organizer != nil && (e.Organizer.DisplayName != "" || e.Organizer.Email != "") {
+		opts = append(opts, meeting.WithOrganizer(meeting.Attendee{
+			Name:  cmp.Or(e.Organizer.DisplayName, e.Organizer.Email),
+			Email: e.Organizer.Email,
+		}))
+	}
+	for _, a := range e.Attendees {
+		if a.Resource {
+			continue
+		}
+		opts = append(opts, meeting.WithAttendees(meeting.Attendee{
+			Name:   cmp.Or(a.DisplayName, a.Email),
+			Email:  a.Email,
+			Status: responseStatus[a.ResponseStatus],
+		}))
+	}
+	return opts, nil
+}
+
+// eventTime returns the time of the start or end of an event in loc.
+func eventTime(t *calendar.EventDateTime, loc *time.Location) (time.Time, error) {
+	switch {
+	case t == nil:
+		return time.Time{}, errors.New("event has no time")
+	case t.DateTime != "":
+		result, err := time.Parse(time.RFC3339, t.DateTime)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid event time %q: %w", t.DateTime, err)
+		}
+		return result.In(loc), nil
+	default:
+		result, err := time.ParseInLocation(time.DateOnly, t.Date, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid event date %q: %w", t.Date, err)
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,168 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/sascha-andres/obsidian-utils/internal/meeting"
+)
+
+func TestStart(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("time.LoadLocation() error = %v", err)
+	}
+	tests := []struct {
+		name       string
+		start      *calendar.EventDateTime
+		want       time.Time
+		wantAllDay bool
+		wantErr    bool
+	}{
+		{
+			name:  "Date time",
+			start: &calendar.EventDateTime{DateTime: "2026-03-02T09:00:00Z"},
+			want:  time.Date(2026, 3, 2, 10, 0, 0, 0, berlin),
+		},
+		{
+			name:       "All day",
+			start:      &calendar.EventDateTime{Date: "2026-03-02"},
+			want:       time.Date(2026, 3, 2, 0, 0, 0, 0, berlin),
+			wantAllDay: true,
+		},
+		{
+			name:    "Invalid",
+			start:   &calendar.EventDateTime{DateTime: "tomorrow"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &calendar.Event{Start: tt.start}
+			got, err := Start(e, berlin)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Start() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) || (!tt.wantErr && got.Location() != berlin) {
+				t.Errorf("Start() = %v, want %v", got, tt.want)
+			}
+			if IsAllDay(e) != tt.wantAllDay {
+				t.Errorf("IsAllDay() = %t, want %t", IsAllDay(e), tt.wantAllDay)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *calendar.Event
+		want  string
+	}{
+		{
+			name:  "Single event",
+			event: &calendar.Event{Id: "abc", ICalUID: "abc@google.com"},
+			want:  "abc@google.com",
+		},
+		{
+			name: "Occurrence",
+			event: &calendar.Event{
+				Id:                "abc_20260302T090000Z",
+				ICalUID:           "abc@google.com",
+				RecurringEventId:  "abc",
+				OriginalStartTime: &calendar.EventDateTime{DateTime: "2026-03-02T09:00:00Z"},
+			},
+			want: "abc@google.com#20260302T090000Z",
+		},
+		{
+			name: "Occurrence with offset",
+			event: &calendar.Event{
+				ICalUID:           "abc@google.com",
+				RecurringEventId:  "abc",
+				OriginalStartTime: &calendar.EventDateTime{DateTime: "2026-03-02T10:00:00+01:00"},
+			},
+			want: "abc@google.com#20260302T090000Z",
+		},
+		{
+			name: "Occurrence with timezone",
+			event: &calendar.Event{
+				ICalUID:           "abc@google.com",
+				RecurringEventId:  "abc",
+				OriginalStartTime: &calendar.EventDateTime{DateTime: "2026-03-02T09:00:00Z", TimeZone: "Europe/Berlin"},
+			},
+			want: "abc@google.com#20260302T100000",
+		},
+		{
+			name: "All-day occurrence",
+			event: &calendar.Event{
+				ICalUID:           "abc@google.com",
+				RecurringEventId:  "abc",
+				OriginalStartTime: &calendar.EventDateTime{Date: "2026-03-02"},
+			},
+			want: "abc@google.com#20260302",
+		},
+		{
+			name:  "No uid",
+			event: &calendar.Event{Id: "abc"},
+			want:  "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Key(tt.event); got != tt.want {
+				t.Errorf("Key() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	event := &calendar.Event{
+		ICalUID:     "abc@google.com",
+		Summary:     "Planning",
+		Location:    "Room 1",
+		Description: "Agenda\n",
+		Start:       &calendar.EventDateTime{DateTime: "2026-03-02T09:00:00Z"},
+		End:         &calendar.EventDateTime{DateTime: "2026-03-02T10:30:00Z"},
+		Organizer:   &calendar.EventOrganizer{DisplayName: "Alice", Email: "alice@example.com"},
+		Attendees: []*calendar.EventAttendee{
+			{DisplayName: "Alice", Email: "alice@example.com", ResponseStatus: "accepted"},
+			{Email: "bob@example.com", ResponseStatus: "declined"},
+			{DisplayName: "Room 1", Email: "room@resource.calendar.google.com", Resource: true},
+		},
+	}
+	opts, err := Options(event, time.UTC)
+	if err != nil {
+		t.Fatalf("Options() error = %v", err)
+	}
+	templateFile := filepath.Join(t.TempDir(), "meeting.md")
+	custom := "{{ .Title }} in {{ .Location }} ({{ .Duration }}), {{ .UID }}\n{{ .Organizer.Name }}\n" +
+		"{{ range .Attendees }}- {{ .Name }} <{{ .Email }}> {{ .Status }}\n{{ end }}{{ .Description }}"
+	if err := os.WriteFile(templateFile, []byte(custom), 0600); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	m, err := meeting.NewMeeting(append(opts, meeting.WithTemplate(templateFile))...)
+	if err != nil {
+		t.Fatalf("NewMeeting() error = %v", err)
+	}
+	start, _ := Start(event, time.UTC)
+	content, err := m.CreateContent(event.Summary, start)
+	if err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	want := "Planning in Room 1 (1h30m), abc@google.com\nAlice\n" +
+		"- Alice <alice@example.com> ACCEPTED\n- bob@example.com <bob@example.com> DECLINED\nAgenda"
+	if content != want {
+		t.Errorf("CreateContent() = %q, want %q", content, want)
+	}
+}
@@ -0,0 +1,69 @@
+package contacts
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"google.golang.org/api/people/v1"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
+)
+
+// BirthdayEntry is the birthday of a contact, Year is 0 if it is unknown.
+type BirthdayEntry struct {
+	Name  string `json:"name"`
+	Year  int    `json:"year,omitempty"`
+	Month int    `json:"month"`
+	Day   int    `json:"day"`
+}
+
+// Birthdays returns the birthdays of the contacts sorted by month and day, birthdays given as text only are left out.
+func Birthdays(contacts []*people.Person) []BirthdayEntry {
+	var result []BirthdayEntry
+	for _, p := range contacts {
+		for _, b := range p.Birthdays {
+			if b.Date == nil || b.Date.Month == 0 || b.Date.Day == 0 {
+				continue
+			}
+			result = append(result, BirthdayEntry{
+				Name:  DisplayName(p),
+				Year:  int(b.Date.Year),
+				Month: int(b.Date.Month),
+				Day:   int(b.Date.Day),
+			})
+			break
+		}
+	}
+	slices.SortFunc(result, func(a, b BirthdayEntry) int {
+		return cmp.Or(cmp.Compare(a.Month, b.Month), cmp.Compare(a.Day, b.Day), cmp.Compare(a.Name, b.Name))
+	})
+	return result
+}
+
+// WriteBirthdays writes the birthdays of the contacts as Markdown list below a headline per month, the contacts are
+// written as wiki links to their person notes.
+func WriteBirthdays(w io.Writer, contacts []*people.Person) error {
+	if _, err := io.WriteString(w, "# Birthdays\n"); err != nil {
+		return err
+	}
+	month := 0
+	for _, b := range Birthdays(contacts) {
+		if b.Month != month {
+			month = b.Month
+			if _, err := fmt.Fprintf(w, "\n## %s\n\n", time.Month(month)); err != nil {
+				return err
+			}
+		}
+		line := fmt.Sprintf("- %02d-%02d [[%s]]", b.Month, b.Day, obsidianutils.SanitizeFileName(b.Name))
+		if b.Year != 0 {
+			line += fmt.Sprintf(" (%d)", b.Year)
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
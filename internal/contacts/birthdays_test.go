@@ -0,0 +1,48 @@
+package contacts
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/api/people/v1"
+)
+
+func TestWriteBirthdays(t *testing.T) {
+	tests := []struct {
+		name     string
+		contacts []*people.Person
+		want     string
+	}{
+		{
+			name: "Sorted by month and day",
+			contacts: []*people.Person{
+				testPerson("", "Carol", withBirthday(0, 12, 24)),
+				testPerson("", "Bob", withBirthday(1980, 4, 7)),
+				{Names: []*people.Name{{DisplayName: "Dave"}}, Birthdays: []*people.Birthday{{Text: "sometime in May"}}},
+				testPerson("", "Alice", withBirthday(1975, 4, 7)),
+				testPerson("", "Eve: QA", withBirthday(1990, 1, 31)),
+				{Names: []*people.Name{{DisplayName: "Frank"}}},
+			},
+			want: "# Birthdays\n\n## January\n\n- 01-31 [[Eve QA]] (1990)\n" +
+				"\n## April\n\n- 04-07 [[Alice]] (1975)\n- 04-07 [[Bob]] (1980)\n" +
+				"\n## December\n\n- 12-24 [[Carol]]\n",
+		},
+		{
+			name:     "No birthdays",
+			contacts: []*people.Person{{Names: []*people.Name{{DisplayName: "Frank"}}}},
+			want:     "# Birthdays\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteBirthdays(&buf, tt.contacts); err != nil {
+				t.Fatalf("WriteBirthdays() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("WriteBirthdays() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
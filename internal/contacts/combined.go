@@ -0,0 +1,80 @@
+package contacts
+
+import (
+	"cmp"
+	"slices"
+
+	"google.golang.org/api/people/v1"
+)
+
+// Combined holds contacts and contact groups joined by the group memberships, so neither has to be looked up in the
+// other.
+type Combined struct {
+	Contacts []CombinedContact `json:"contacts"`
+	Groups   []CombinedGroup   `json:"groups"`
+}
+
+// CombinedContact is a contact with the names of its groups and the matching tags, e.g. contact-group/book-club.
+type CombinedContact struct {
+	FlatContact
+	Tags []string `json:"tags,omitempty"`
+}
+
+// CombinedGroup is a contact group created by the user with its members sorted by name.
+type CombinedGroup struct {
+	ResourceName string   `json:"resourceName"`
+	Name         string   `json:"name"`
+	Tag          string   `json:"tag"`
+	Members      []Member `json:"members"`
+}
+
+// Member is a contact listed as member of a group.
+type Member struct {
+	ResourceName string `json:"resourceName"`
+	Name         string `json:"name"`
+}
+
+// Combine joins the contacts and the groups created by the user. System groups like myContacts are left out, as
+// nearly every contact is a member. Groups are sorted by name.
+func Combine(contacts []*people.Person, groups []*people.ContactGroup) Combined {
+	groupNames := GroupNames(groups)
+	members := make(map[string][]Member, len(groupNames))
+	result := Combined{Contacts: make([]CombinedContact, 0, len(contacts)), Groups: make([]CombinedGroup, 0, len(groupNames))}
+	for _, p := range contacts {
+		c := CombinedContact{FlatContact: flatten(p, groupNames)}
+		for _, name := range c.Groups {
+			c.Tags = append(c.Tags, Tag(GroupTagPrefix, name))
+		}
+		result.Contacts = append(result.Contacts, c)
+
+		for _, m := range p.Memberships {
+			if m.ContactGroupMembership == nil {
+				continue
+			}
+			resourceName := m.ContactGroupMembership.ContactGroupResourceName
+			if _, ok := groupNames[resourceName]; ok {
+				members[resourceName] = append(members[resourceName], Member{ResourceName: p.ResourceName, Name: c.Name})
+			}
+		}
+	}
+	for resourceName, name := range groupNames {
+		m := members[resourceName]
+		if m == nil {
+			m = []Member{}
+		}
+		slices.SortFunc(m, func(a, b Member) int {
+			return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ResourceName, b.ResourceName))
+		})
+		m = slices.Compact(m)
+		result.Groups = append(result.Groups, CombinedGroup{
+			ResourceName: resourceName,
+			Name:         name,
+			Tag:          Tag(GroupTagPrefix, name),
+			Members:      m,
+		})
+	}
+	slices.SortFunc(result.Groups, func(a, b CombinedGroup) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ResourceName, b.ResourceName))
+	})
+	return result
+}
@@ -0,0 +1,79 @@
+package contacts
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/people/v1"
+)
+
+func TestCombine(t *testing.T) {
+	groups := []*people.ContactGroup{
+		{ResourceName: "contactGroups/myContacts", Name: "myContacts", GroupType: "SYSTEM_CONTACT_GROUP"},
+		{ResourceName: "contactGroups/b", Name: "Book Club", GroupType: "USER_CONTACT_GROUP"},
+		{ResourceName: "contactGroups/a", Name: "Family", GroupType: "USER_CONTACT_GROUP"},
+		{ResourceName: "contactGroups/e", Name: "Empty", GroupType: "USER_CONTACT_GROUP"},
+	}
+	tests := []struct {
+		name     string
+		contacts []*people.Person
+		want     Combined
+	}{
+		{
+			name: "Members and group names",
+			contacts: []*people.Person{
+				testPerson("people/c1", "Carol", withGroups("contactGroups/myContacts", "contactGroups/b", "contactGroups/a")),
+				testPerson("people/c2", "Alice", withGroups("contactGroups/myContacts", "contactGroups/b")),
+				testPerson("people/c3", "Bob", withGroups("contactGroups/unknown")),
+			},
+			want: Combined{
+				Contacts: []CombinedContact{
+					{
+						FlatContact: FlatContact{ResourceName: "people/c1", Name: "Carol", Groups: []string{"Book Club", "Family"}},
+						Tags:        []string{"contact-group/book-club", "contact-group/family"},
+					},
+					{
+						FlatContact: FlatContact{ResourceName: "people/c2", Name: "Alice", Groups: []string{"Book Club"}},
+						Tags:        []string{"contact-group/book-club"},
+					},
+					{FlatContact: FlatContact{ResourceName: "people/c3", Name: "Bob"}},
+				},
+				Groups: []CombinedGroup{
+					{
+						ResourceName: "contactGroups/b",
+						Name:         "Book Club",
+						Tag:          "contact-group/book-club",
+						Members:      []Member{{ResourceName: "people/c2", Name: "Alice"}, {ResourceName: "people/c1", Name: "Carol"}},
+					},
+					{ResourceName: "contactGroups/e", Name: "Empty", Tag: "contact-group/empty", Members: []Member{}},
+					{
+						ResourceName: "contactGroups/a",
+						Name:         "Family",
+						Tag:          "contact-group/family",
+						Members:      []Member{{ResourceName: "people/c1", Name: "Carol"}},
+					},
+				},
+			},
+		},
+		{
+			name: "No contacts",
+			want: Combined{
+				Contacts: []CombinedContact{},
+				Groups: []CombinedGroup{
+					{ResourceName: "contactGroups/b", Name: "Book Club", Tag: "contact-group/book-club", Members: []Member{}},
+					{ResourceName: "contactGroups/e", Name: "Empty", Tag: "contact-group/empty", Members: []Member{}},
+					{ResourceName: "contactGroups/a", Name: "Family", Tag: "contact-group/family", Members: []Member{}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Combine(tt.contacts, groups)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Combine() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package contacts
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"google.golang.org/api/people/v1"
+)
+
+// csvHeader are the columns written by WriteCSV.
+var csvHeader = []string{
+	"resource name", "name", "given name", "family name", "nicknames", "emails", "phones", "addresses",
+	"organization", "job title", "department", "birthday", "events", "relations", "urls", "groups",
+}
+
+// csvSeparator separates the values of columns with several values.
+const csvSeparator = "; "
+
+// WriteCSV writes the contacts as CSV with a header line, one contact per line. Columns with several values like
+// emails separate them with a semicolon. groupNames is used to resolve the group memberships.
+func WriteCSV(w io.Writer, contacts []*people.Person, groupNames map[string]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, f := range Flatten(contacts, groupNames) {
+		record := []string{
+			f.ResourceName, f.Name, f.GivenName, f.FamilyName,
+			strings.Join(f.Nicknames, csvSeparator), strings.Join(f.Emails, csvSeparator),
+			strings.Join(f.Phones, csvSeparator), strings.Join(f.Addresses, csvSeparator),
+			f.Organization, f.JobTitle, f.Department, f.Birthday,
+			strings.Join(f.Events, csvSeparator), strings.Join(f.Relations, csvSeparator),
+			strings.Join(f.URLs, csvSeparator), strings.Join(f.Groups, csvSeparator),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,51 @@
+package contacts
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/api/people/v1"
+)
+
+func TestWriteCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		contacts []*people.Person
+		want     string
+	}{
+		{
+			name:     "No contacts",
+			contacts: nil,
+			want: "resource name,name,given name,family name,nicknames,emails,phones,addresses,organization,job title," +
+				"department,birthday,events,relations,urls,groups\n",
+		},
+		{
+			name: "Several values",
+			contacts: []*people.Person{{
+				ResourceName: "people/c1",
+				Names:        []*people.Name{{DisplayName: "Doe, Alice", GivenName: "Alice", FamilyName: "Doe"}},
+				EmailAddresses: []*people.EmailAddress{
+					{Value: "alice@example.com"}, {Value: "alice@work.example.com"},
+				},
+				Memberships: []*people.Membership{
+					{ContactGroupMembership: &people.ContactGroupMembership{ContactGroupResourceName: "contactGroups/friends"}},
+				},
+			}},
+			want: "resource name,name,given name,family name,nicknames,emails,phones,addresses,organization,job title," +
+				"department,birthday,events,relations,urls,groups\n" +
+				`people/c1,"Doe, Alice",Alice,Doe,,alice@example.com; alice@work.example.com,,,,,,,,,,Friends` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteCSV(&buf, tt.contacts, map[string]string{"contactGroups/friends": "Friends"}); err != nil {
+				t.Fatalf("WriteCSV() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("WriteCSV() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
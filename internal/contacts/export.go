@@ -0,0 +1,35 @@
+package contacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"google.golang.org/api/people/v1"
+)
+
+// ReadContacts reads contacts exported as JSON, e.g. a contacts.json written by ggl.
+func ReadContacts(fileName string) ([]*people.Person, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var result []*people.Person
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unable to read contacts from %s: %w", fileName, err)
+	}
+	return result, nil
+}
+
+// ReadGroups reads contact groups exported as JSON, e.g. a groups.json written by ggl.
+func ReadGroups(fileName string) ([]*people.ContactGroup, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var result []*people.ContactGroup
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unable to read groups from %s: %w", fileName, err)
+	}
+	return result, nil
+}
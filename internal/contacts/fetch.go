@@ -0,0 +1,221 @@
+package contacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/people/v1"
+
+	"github.com/sascha-andres/obsidian-utils/internal/google"
+)
+
+// DefaultPersonFields are the person fields requested for each contact.
+const DefaultPersonFields = "names,emailAddresses,phoneNumbers,addresses,organizations,memberships,birthdays"
+
+// PersonFields are all person fields the People API returns for contacts, "all" may be passed to
+// WithPersonFields as shortcut.
+var PersonFields = []string{
+	"addresses", "ageRanges", "biographies", "birthdays", "calendarUrls", "clientData", "coverPhotos",
+	"emailAddresses", "events", "externalIds", "genders", "imClients", "interests", "locales", "locations",
+	"memberships", "metadata", "miscKeywords", "names", "nicknames", "occupations", "organizations", "phoneNumbers",
+	"photos", "relations", "sipAddresses", "skills", "urls", "userDefined",
+}
+
+// maxPageSize is the largest page size the People API accepts for contacts and contact groups.
+const maxPageSize = 1000
+
+// ErrSyncTokenExpired is returned by Changes if the sync token is no longer accepted, a full sync is required.
+var ErrSyncTokenExpired = errors.New("sync token expired")
+
+// Client fetches contacts and contact groups from the People API, following all pages and retrying requests that
+// failed because of rate limits or server errors.
+type Client struct {
+	srv          *people.Service
+	personFields []string
+	pageSize     int64
+	retries      int
+	backoff      time.Duration
+	progress     func(kind string, fetched int)
+}
+
+// OptionFunc defines a function type that modifies a Client instance or returns an error.
+type OptionFunc func(c *Client) error
+
+// WithPersonFields sets the person fields requested for each contact, a comma separated list of PersonFields or
+// "all".
+func WithPersonFields(fields string) OptionFunc {
+	return func(c *Client) error {
+		if strings.TrimSpace(fields) == "all" {
+			c.personFields = slices.Clone(PersonFields)
+			return nil
+		}
+		c.personFields = nil
+		for _, f := range strings.Split(fields, ",") {
+			f = strings.TrimSpace(f)
+			if f == "" || slices.Contains(c.personFields, f) {
+				continue
+			}
+			if !slices.Contains(PersonFields, f) {
+				return fmt.Errorf("unknown person field %q", f)
+			}
+			c.personFields = append(c.personFields, f)
+		}
+		if len(c.personFields) == 0 {
+			return errors.New("no person fields")
+		}
+		return nil
+	}
+}
+
+// WithPageSize sets the number of contacts or groups requested per page, at most 1000.
+func WithPageSize(size int) OptionFunc {
+	return func(c *Client) error {
+		if size < 1 || size > maxPageSize {
+			return fmt.Errorf("invalid page size %d, expected 1 to %d", size, maxPageSize)
+		}
+		c.pageSize = int64(size)
+		return nil
+	}
+}
+
+// WithRetries sets how often a request is retried and the wait before the first retry, which doubles with every
+// further retry. A Retry-After header sent by the API takes precedence.
+func WithRetries(retries int, backoff time.Duration) OptionFunc {
+	return func(c *Client) error {
+		if retries < 0 {
+			return fmt.Errorf("invalid number of retries %d", retries)
+		}
+		c.retries = retries
+		c.backoff = backoff
+		return nil
+	}
+}
+
+// WithProgress sets a function called after each page with the kind of data ("contacts" or "groups") and the number
+// of items fetched so far.
+func WithProgress(progress func(kind string, fetched int)) OptionFunc {
+	return func(c *Client) error {
+		c.progress = progress
+		return nil
+	}
+}
+
+// NewClient initializes and returns a new Client using the People service with the provided options.
+func NewClient(srv *people.Service, opts ...OptionFunc) (*Client, error) {
+	c := &Client{
+		srv:          srv,
+		personFields: strings.Split(DefaultPersonFields, ","),
+		pageSize:     maxPageSize,
+		retries:      5,
+		backoff:      time.Second,
+		progress:     func(string, int) {},
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Connections returns all contacts of the authenticated user.
+func (c *Client) Connections(ctx context.Context) ([]*people.Person, error) {
+	result, _, err := c.connections(ctx, "", false)
+	return result, err
+}
+
+// PersonFields returns the person fields requested for each contact as comma separated list.
+func (c *Client) PersonFields() string {
+	return strings.Join(c.personFields, ",")
+}
+
+// Sync returns all contacts of the authenticated user and a sync token to get the changes since with Changes.
+func (c *Client) Sync(ctx context.Context) ([]*people.Person, string, error) {
+	return c.connections(ctx, "", true)
+}
+
+// Changes returns the contacts added, changed or deleted since the sync token was issued and a new sync token.
+// Deleted contacts are marked as deleted in their metadata and contain nothing but the resource name.
+func (c *Client) Changes(ctx context.Context, syncToken string) ([]*people.Person, string, error) {
+	result, next, err := c.connections(ctx, syncToken, true)
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && (apiErr.Code == http.StatusGone || strings.Contains(apiErr.Body, "EXPIRED_SYNC_TOKEN")) {
+		return nil, "", fmt.Errorf("%w: %w", ErrSyncTokenExpired, err)
+	}
+	return result, next, err
+}
+
+// connections lists the contacts following all pages. With a sync token only the changes since are listed. The sync
+// token for the next request is returned if one is requested.
+func (c *Client) connections(ctx context.Context, syncToken string, requestSyncToken bool) ([]*people.Person, string, error) {
+	fields := c.personFields
+	if requestSyncToken && !slices.Contains(fields, "metadata") {
+		// the metadata tells whether a contact was deleted
+		fields = append(slices.Clone(fields), "metadata")
+	}
+	var (
+		result    []*people.Person
+		pageToken string
+	)
+	for {
+		var r *people.ListConnectionsResponse
+		err := google.Retry(ctx, c.retries, c.backoff, func() (err error) {
+			call := c.srv.People.Connections.List("people/me").
+				PersonFields(strings.Join(fields, ",")).
+				PageSize(c.pageSize).
+				PageToken(pageToken).
+				Context(ctx)
+			if requestSyncToken {
+				call = call.RequestSyncToken(true)
+			}
+			if syncToken != "" {
+				call = call.SyncToken(syncToken)
+			}
+			r, err = call.Do()
+			return err
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to retrieve contacts: %w", err)
+		}
+		result = append(result, r.Connections...)
+		c.progress("contacts", len(result))
+		if r.NextPageToken == "" {
+			return result, r.NextSyncToken, nil
+		}
+		pageToken = r.NextPageToken
+	}
+}
+
+// Groups returns all contact groups of the authenticated user.
+func (c *Client) Groups(ctx context.Context) ([]*people.ContactGroup, error) {
+	var (
+		result    []*people.ContactGroup
+		pageToken string
+	)
+	for {
+		var r *people.ListContactGroupsResponse
+		err := google.Retry(ctx, c.retries, c.backoff, func() (err error) {
+			r, err = c.srv.ContactGroups.List().
+				PageSize(c.pageSize).
+				PageToken(pageToken).
+				Context(ctx).
+				Do()
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve contact groups: %w", err)
+		}
+		result = append(result, r.ContactGroups...)
+		c.progress("groups", len(result))
+		if r.NextPageToken == "" {
+			return result, nil
+		}
+		pageToken = r.NextPageToken
+	}
+}
@@ -0,0 +1,229 @@
+package contacts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/people/v1"
+
+	"github.com/sascha-andres/obsidian-utils/internal/google/googletest"
+)
+
+// fakePeopleAPI serves contacts and contact groups in pages of two like the People API. The first failures
+// requests are answered with 429 Too Many Requests. Requests with syncToken get the changes, other sync tokens are
+// rejected as expired. If a sync token is requested, "next-token" is returned on the last page.
+type fakePeopleAPI struct {
+	contacts  []*people.Person
+	groups    []*people.ContactGroup
+	syncToken string
+	changes   []*people.Person
+	failures  int
+	requests  int
+}
+
+func (f *fakePeopleAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests++
+	if f.failures > 0 {
+		f.failures--
+		googletest.Error(w, http.StatusTooManyRequests, "quota exceeded")
+		return
+	}
+	token := r.URL.Query().Get("pageToken")
+	var response any
+	switch r.URL.Path {
+	case "/v1/people/me/connections":
+		contacts := f.contacts
+		if syncToken := r.URL.Query().Get("syncToken"); syncToken != "" {
+			if syncToken != f.syncToken {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprint(w, `{"error":{"code":400,"message":"Sync token is expired.","status":"FAILED_PRECONDITION","details":[{"reason":"EXPIRED_SYNC_TOKEN"}]}}`)
+				return
+			}
+			contacts = f.changes
+		}
+		contacts, next := googletest.Page(contacts, token)
+		list := people.ListConnectionsResponse{Connections: contacts, NextPageToken: next}
+		if next == "" && r.URL.Query().Get("requestSyncToken") == "true" {
+			list.NextSyncToken = "next-token"
+		}
+		response = list
+	case "/v1/contactGroups":
+		groups, next := googletest.Page(f.groups, token)
+		response = people.ListContactGroupsResponse{ContactGroups: groups, NextPageToken: next}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// newTestService returns a People service using the fake API.
+func newTestService(t *testing.T, api http.Handler) *people.Service {
+	t.Helper()
+	srv, err := people.NewService(context.Background(), googletest.Options(t, api)...)
+	if err != nil {
+		t.Fatalf("people.NewService() error = %v", err)
+	}
+	return srv
+}
+
+func TestWithPersonFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "Listed fields",
+			fields: "names, emailAddresses,names",
+			want:   "names,emailAddresses",
+		},
+		{
+			name:   "All fields",
+			fields: "all",
+			want:   strings.Join(PersonFields, ","),
+		},
+		{
+			name:    "Unknown field",
+			fields:  "names,shoeSize",
+			wantErr: true,
+		},
+		{
+			name:    "No fields",
+			fields:  " , ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(nil, WithPersonFields(tt.fields))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && c.PersonFields() != tt.want {
+				t.Errorf("PersonFields() = %q, want %q", c.PersonFields(), tt.want)
+			}
+		})
+	}
+}
+
+func TestConnections(t *testing.T) {
+	var contacts []*people.Person
+	for i := range 5 {
+		contacts = append(contacts, &people.Person{ResourceName: fmt.Sprintf("people/c%d", i)})
+	}
+	tests := []struct {
+		name         string
+		failures     int
+		retries      int
+		wantErr      bool
+		wantRequests int
+	}{
+		{
+			name:         "All pages",
+			wantRequests: 3,
+		},
+		{
+			name:         "Retry on rate limit",
+			failures:     2,
+			retries:      2,
+			wantRequests: 5,
+		},
+		{
+			name:         "Retries used up",
+			failures:     3,
+			retries:      2,
+			wantErr:      true,
+			wantRequests: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakePeopleAPI{contacts: contacts, failures: tt.failures}
+			var progress []int
+			c, err := NewClient(newTestService(t, api),
+				WithPageSize(2),
+				WithRetries(tt.retries, time.Millisecond),
+				WithProgress(func(kind string, fetched int) { progress = append(progress, fetched) }))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			got, err := c.Connections(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Connections() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if api.requests != tt.wantRequests {
+				t.Errorf("Connections() made %d requests, want %d", api.requests, tt.wantRequests)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(contacts) || got[4].ResourceName != "people/c4" {
+				t.Errorf("Connections() returned %d contacts, want %d", len(got), len(contacts))
+			}
+			if fmt.Sprint(progress) != "[2 4 5]" {
+				t.Errorf("Connections() reported progress %v, want [2 4 5]", progress)
+			}
+		})
+	}
+}
+
+func TestGroups(t *testing.T) {
+	api := &fakePeopleAPI{groups: []*people.ContactGroup{
+		{ResourceName: "contactGroups/friends", Name: "Friends"},
+		{ResourceName: "contactGroups/family", Name: "Family"},
+		{ResourceName: "contactGroups/work", Name: "Work"},
+	}}
+	c, err := NewClient(newTestService(t, api), WithPageSize(2))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	got, err := c.Groups(context.Background())
+	if err != nil {
+		t.Fatalf("Groups() error = %v", err)
+	}
+	if len(got) != 3 || got[2].Name != "Work" {
+		t.Errorf("Groups() = %d groups, want 3", len(got))
+	}
+	if api.requests != 2 {
+		t.Errorf("Groups() made %d requests, want 2", api.requests)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	api := &fakePeopleAPI{
+		contacts:  []*people.Person{{ResourceName: "people/c1"}, {ResourceName: "people/c2"}, {ResourceName: "people/c3"}},
+		syncToken: "token",
+		changes: []*people.Person{
+			{ResourceName: "people/c2", Metadata: &people.PersonMetadata{Deleted: true}},
+			{ResourceName: "people/c4"},
+		},
+	}
+	c, err := NewClient(newTestService(t, api), WithPageSize(2))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	all, next, err := c.Sync(context.Background())
+	if err != nil || len(all) != 3 || next != "next-token" {
+		t.Errorf("Sync() = %d contacts, %q, %v, want 3 contacts, %q", len(all), next, err, "next-token")
+	}
+	changes, next, err := c.Changes(context.Background(), "token")
+	if err != nil || len(changes) != 2 || next != "next-token" {
+		t.Errorf("Changes() = %d contacts, %q, %v, want 2 contacts, %q", len(changes), next, err, "next-token")
+	}
+	if _, _, err := c.Changes(context.Background(), "old"); !errors.Is(err, ErrSyncTokenExpired) {
+		t.Errorf("Changes() error = %v, want %v", err, ErrSyncTokenExpired)
+	}
+}
@@ -0,0 +1,112 @@
+package contacts
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/people/v1"
+)
+
+// FlatContact is a contact reduced to plain values, easy to use in templates and scripts. Empty values are left out
+// when written as JSON.
+type FlatContact struct {
+	ResourceName string   `json:"resourceName"`
+	Name         string   `json:"name"`
+	GivenName    string   `json:"givenName,omitempty"`
+	FamilyName   string   `json:"familyName,omitempty"`
+	Nicknames    []string `json:"nicknames,omitempty"`
+	Emails       []string `json:"emails,omitempty"`
+	Phones       []string `json:"phones,omitempty"`
+	Addresses    []string `json:"addresses,omitempty"`
+	Organization string   `json:"organization,omitempty"`
+	JobTitle     string   `json:"jobTitle,omitempty"`
+	Department   string   `json:"department,omitempty"`
+	Birthday     string   `json:"birthday,omitempty"`
+	Events       []string `json:"events,omitempty"`
+	Relations    []string `json:"relations,omitempty"`
+	URLs         []string `json:"urls,omitempty"`
+	Biography    string   `json:"biography,omitempty"`
+	Photo        string   `json:"photo,omitempty"`
+	Groups       []string `json:"groups,omitempty"`
+}
+
+// Flatten returns the contacts as FlatContact, groupNames is used to resolve the group memberships.
+func Flatten(contacts []*people.Person, groupNames map[string]string) []FlatContact {
+	result := make([]FlatContact, 0, len(contacts))
+	for _, p := range contacts {
+		result = append(result, flatten(p, groupNames))
+	}
+	return result
+}
+
+// flatten returns the contact as FlatContact.
+func flatten(p *people.Person, groupNames map[string]string) FlatContact {
+	f := FlatContact{
+		ResourceName: p.ResourceName,
+		Name:         DisplayName(p),
+		Emails:       Emails(p),
+		Phones:       Phones(p),
+		Birthday:     Birthday(p),
+		Groups:       Groups(p, groupNames),
+	}
+	if len(p.Names) > 0 {
+		f.GivenName, f.FamilyName = p.Names[0].GivenName, p.Names[0].FamilyName
+	}
+	for _, n := range p.Nicknames {
+		f.Nicknames = append(f.Nicknames, n.Value)
+	}
+	for _, a := range p.Addresses {
+		if value := FormatAddress(a); value != "" {
+			f.Addresses = append(f.Addresses, value)
+		}
+	}
+	if o := Organization(p); o != nil {
+		f.Organization, f.JobTitle, f.Department = o.Name, o.Title, o.Department
+	}
+	for _, e := range p.Events {
+		if e.Date != nil {
+			f.Events = append(f.Events, fmt.Sprintf("%s: %s", label(e.FormattedType, e.Type), FormatDate(e.Date)))
+		}
+	}
+	for _, r := range p.Relations {
+		f.Relations = append(f.Relations, fmt.Sprintf("%s: %s", label(r.FormattedType, r.Type), r.Person))
+	}
+	for _, u := range p.Urls {
+		f.URLs = append(f.URLs, u.Value)
+	}
+	if len(p.Biographies) > 0 {
+		f.Biography = p.Biographies[0].Value
+	}
+	for _, photo := range p.Photos {
+		if !photo.Default {
+			f.Photo = photo.Url
+			break
+		}
+	}
+	return f
+}
+
+// FormatAddress returns the address on one line, using the formatted value of the API if there is one.
+func FormatAddress(a *people.Address) string {
+	if a.FormattedValue != "" {
+		return strings.Join(strings.Fields(strings.ReplaceAll(a.FormattedValue, "\n", ", ")), " ")
+	}
+	var parts []string
+	for _, part := range []string{a.StreetAddress, a.ExtendedAddress, strings.TrimSpace(a.PostalCode + " " + a.City), a.Region, a.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// label returns the formatted type, the type if there is none or "other".
+func label(formattedType, typ string) string {
+	switch {
+	case formattedType != "":
+		return formattedType
+	case typ != "":
+		return typ
+	}
+	return "other"
+}
@@ -0,0 +1,54 @@
+package contacts
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/people/v1"
+)
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name   string
+		person *people.Person
+		want   FlatContact
+	}{
+		{
+			name:   "All fields",
+			person: fullPerson(),
+			want: FlatContact{
+				ResourceName: "people/c1",
+				Name:         "Alice Doe",
+				GivenName:    "Alice",
+				FamilyName:   "Doe",
+				Nicknames:    []string{"Ali"},
+				Emails:       []string{"alice@example.com"},
+				Phones:       []string{"0171 123"},
+				Addresses:    []string{"Main Street 1, 12345 Town", "Work Road 2, 54321 City, Germany"},
+				Organization: "ACME",
+				JobTitle:     "Engineer",
+				Department:   "R&D",
+				Birthday:     "--04-07",
+				Events:       []string{"anniversary: 2010-06-01"},
+				Relations:    []string{"Spouse: Bob"},
+				URLs:         []string{"https://example.com"},
+				Biography:    "Met at the conference",
+				Groups:       []string{"Friends"},
+			},
+		},
+		{
+			name:   "Only a name",
+			person: &people.Person{ResourceName: "people/c2", Names: []*people.Name{{DisplayName: "Bob"}}},
+			want:   FlatContact{ResourceName: "people/c2", Name: "Bob"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Flatten([]*people.Person{tt.person}, testGroupNames)
+			if diff := cmp.Diff([]FlatContact{tt.want}, got); diff != "" {
+				t.Errorf("Flatten() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
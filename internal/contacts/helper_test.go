@@ -0,0 +1,67 @@
+package contacts
+
+import "google.golang.org/api/people/v1"
+
+// testGroupNames names the groups of the test contacts.
+var testGroupNames = map[string]string{"contactGroups/friends": "Friends"}
+
+// testPerson returns a contact with the resource name and display name, changed by the options.
+func testPerson(resourceName, name string, opts ...func(p *people.Person)) *people.Person {
+	p := &people.Person{ResourceName: resourceName, Names: []*people.Name{{DisplayName: name}}}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// fullPerson returns a contact using most of the person fields.
+func fullPerson() *people.Person {
+	return testPerson("people/c1", "Alice Doe", withEmails("alice@example.com"), withGroups("contactGroups/friends"),
+		withBirthday(0, 4, 7), func(p *people.Person) {
+			p.Names[0].GivenName, p.Names[0].FamilyName = "Alice", "Doe"
+			p.Nicknames = []*people.Nickname{{Value: "Ali"}}
+			p.EmailAddresses[0].Type = "work"
+			p.PhoneNumbers = []*people.PhoneNumber{{Value: "0171 123", CanonicalForm: "+49171123", Type: "mobile"}}
+			p.Addresses = []*people.Address{
+				{FormattedValue: "Main Street 1\n12345 Town", Type: "home"},
+				{StreetAddress: "Work Road 2", PostalCode: "54321", City: "City", Country: "Germany", Type: "work"},
+			}
+			p.Organizations = []*people.Organization{{Name: "ACME", Title: "Engineer", Department: "R&D"}}
+			p.Events = []*people.Event{{Date: &people.Date{Year: 2010, Month: 6, Day: 1}, Type: "anniversary"}}
+			p.Relations = []*people.Relation{{Person: "Bob", Type: "spouse", FormattedType: "Spouse"}}
+			p.Urls = []*people.Url{{Value: "https://example.com"}}
+			p.Biographies = []*people.Biography{{Value: "Met at the conference"}}
+		})
+}
+
+// withEtag sets the etag of the contact.
+func withEtag(etag string) func(p *people.Person) {
+	return func(p *people.Person) { p.Etag = etag }
+}
+
+// withEmails adds the email addresses to the contact.
+func withEmails(emails ...string) func(p *people.Person) {
+	return func(p *people.Person) {
+		for _, e := range emails {
+			p.EmailAddresses = append(p.EmailAddresses, &people.EmailAddress{Value: e})
+		}
+	}
+}
+
+// withGroups adds the contact to the groups.
+func withGroups(groups ...string) func(p *people.Person) {
+	return func(p *people.Person) {
+		for _, g := range groups {
+			p.Memberships = append(p.Memberships, &people.Membership{
+				ContactGroupMembership: &people.ContactGroupMembership{ContactGroupResourceName: g},
+			})
+		}
+	}
+}
+
+// withBirthday sets the birthday of the contact, a zero year is unknown.
+func withBirthday(year, month, day int64) func(p *people.Person) {
+	return func(p *people.Person) {
+		p.Birthdays = []*people.Birthday{{Date: &people.Date{Year: year, Month: month, Day: day}}}
+	}
+}
@@ -0,0 +1,160 @@
+package contacts
+
+import (
+	"slices"
+	"strings"
+
+	"google.golang.org/api/people/v1"
+)
+
+// Account holds the contacts and contact groups exported from one Google account.
+type Account struct {
+	Name     string
+	Contacts []*people.Person
+	Groups   []*people.ContactGroup
+}
+
+// Merge joins the exports of several accounts into one set of contacts and groups. Groups created by the user with the
+// same name become one group, system groups like myContacts are joined by their resource name. Contacts sharing an
+// email address are joined: the contact of the first account is kept and gets the email addresses, phone numbers, urls
+// and group memberships of the others, other fields are only taken over if it has none. The members of the groups are
+// counted anew.
+func Merge(accounts []Account) ([]*people.Person, []*people.ContactGroup) {
+	var (
+		contacts []*people.Person
+		groups   []*people.ContactGroup
+		byKey    = make(map[string]*people.ContactGroup)
+		byEmail  = make(map[string]*people.Person)
+	)
+	for _, a := range accounts {
+		// renamed maps the resource names of the groups of the account to the resource names in the merged set
+		renamed := make(map[string]string, len(a.Groups))
+		for _, g := range a.Groups {
+			key := g.ResourceName
+			if g.GroupType == userContactGroup {
+				key = userContactGroup + "/" + g.Name
+			}
+			if merged, ok := byKey[key]; ok {
+				renamed[g.ResourceName] = merged.ResourceName
+				continue
+			}
+			merged := *g
+			merged.MemberResourceNames = nil
+			if slices.ContainsFunc(groups, func(o *people.ContactGroup) bool { return o.ResourceName == g.ResourceName }) {
+				// another group of another account has the same resource name
+				merged.ResourceName = g.ResourceName + "-" + a.Name
+			}
+			byKey[key] = &merged
+			groups = append(groups, &merged)
+			renamed[g.ResourceName] = merged.ResourceName
+		}
+
+		for _, p := range a.Contacts {
+			c := *p
+			c.Memberships = renameMemberships(p.Memberships, renamed)
+			if known := findByEmail(byEmail, &c); known != nil {
+				mergePerson(known, &c)
+				indexEmails(byEmail, known)
+				continue
+			}
+			indexEmails(byEmail, &c)
+			contacts = append(contacts, &c)
+		}
+	}
+	memberCounts := make(map[string]int64, len(groups))
+	for _, p := range contacts {
+		for _, m := range p.Memberships {
+			if m.ContactGroupMembership != nil {
+				memberCounts[m.ContactGroupMembership.ContactGroupResourceName]++
+			}
+		}
+	}
+	for _, g := range groups {
+		g.MemberCount = memberCounts[g.ResourceName]
+	}
+	return contacts, groups
+}
+
+// renameMemberships returns copies of the memberships referring to the groups of the merged set.
+func renameMemberships(memberships []*people.Membership, renamed map[string]string) []*people.Membership {
+	var result []*people.Membership
+	for _, m := range memberships {
+		c := *m
+		if m.ContactGroupMembership != nil {
+			g := *m.ContactGroupMembership
+			if name, ok := renamed[g.ContactGroupResourceName]; ok {
+				g.ContactGroupResourceName = name
+			}
+			c.ContactGroupMembership = &g
+		}
+		result = append(result, &c)
+	}
+	return result
+}
+
+// findByEmail returns the contact already known by one of the email addresses of p, nil if there is none.
+func findByEmail(byEmail map[string]*people.Person, p *people.Person) *people.Person {
+	for _, e := range p.EmailAddresses {
+		if known, ok := byEmail[strings.ToLower(e.Value)]; ok {
+			return known
+		}
+	}
+	return nil
+}
+
+// indexEmails records the contact for each of its email addresses.
+func indexEmails(byEmail map[string]*people.Person, p *people.Person) {
+	for _, e := range p.EmailAddresses {
+		if _, ok := byEmail[strings.ToLower(e.Value)]; !ok && e.Value != "" {
+			byEmail[strings.ToLower(e.Value)] = p
+		}
+	}
+}
+
+// mergePerson adds the values of other to p.
+func mergePerson(p, other *people.Person) {
+	p.EmailAddresses = appendMissing(p.EmailAddresses, other.EmailAddresses, func(e *people.EmailAddress) string {
+		return strings.ToLower(e.Value)
+	})
+	p.PhoneNumbers = appendMissing(p.PhoneNumbers, other.PhoneNumbers, func(n *people.PhoneNumber) string {
+		if n.CanonicalForm != "" {
+			return n.CanonicalForm
+		}
+		return n.Value
+	})
+	p.Urls = appendMissing(p.Urls, other.Urls, func(u *people.Url) string { return u.Value })
+	p.Memberships = appendMissing(p.Memberships, other.Memberships, func(m *people.Membership) string {
+		if m.ContactGroupMembership == nil {
+			return ""
+		}
+		return m.ContactGroupMembership.ContactGroupResourceName
+	})
+	p.Names = orOther(p.Names, other.Names)
+	p.Nicknames = orOther(p.Nicknames, other.Nicknames)
+	p.Addresses = orOther(p.Addresses, other.Addresses)
+	p.Organizations = orOther(p.Organizations, other.Organizations)
+	p.Birthdays = orOther(p.Birthdays, other.Birthdays)
+	p.Events = orOther(p.Events, other.Events)
+	p.Relations = orOther(p.Relations, other.Relations)
+	p.Biographies = orOther(p.Biographies, other.Biographies)
+	p.Photos = orOther(p.Photos, other.Photos)
+}
+
+// appendMissing appends the values of other whose key is not in values yet.
+func appendMissing[T any](values, other []T, key func(T) string) []T {
+	result := slices.Clone(values)
+	for _, o := range other {
+		if !slices.ContainsFunc(result, func(v T) bool { return key(v) == key(o) }) {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+// orOther returns values, or other if there are no values.
+func orOther[T any](values, other []T) []T {
+	if len(values) > 0 {
+		return values
+	}
+	return other
+}
@@ -0,0 +1,88 @@
+package contacts
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/people/v1"
+)
+
+func TestMerge(t *testing.T) {
+	myContacts := &people.ContactGroup{ResourceName: "contactGroups/myContacts", Name: "myContacts", GroupType: "SYSTEM_CONTACT_GROUP"}
+	tests := []struct {
+		name         string
+		accounts     []Account
+		wantContacts []*people.Person
+		wantGroups   []*people.ContactGroup
+	}{
+		{
+			name: "Groups joined by name",
+			accounts: []Account{
+				{
+					Name:     "work",
+					Contacts: []*people.Person{testPerson("people/c1", "Alice", withGroups("contactGroups/myContacts", "contactGroups/a1"))},
+					Groups: []*people.ContactGroup{
+						myContacts,
+						{ResourceName: "contactGroups/a1", Name: "Book Club", GroupType: "USER_CONTACT_GROUP"},
+					},
+				},
+				{
+					Name:     "private",
+					Contacts: []*people.Person{testPerson("people/c2", "Bob", withGroups("contactGroups/myContacts", "contactGroups/b1", "contactGroups/a1"))},
+					Groups: []*people.ContactGroup{
+						myContacts,
+						{ResourceName: "contactGroups/b1", Name: "Book Club", GroupType: "USER_CONTACT_GROUP"},
+						{ResourceName: "contactGroups/a1", Name: "Family", GroupType: "USER_CONTACT_GROUP"},
+					},
+				},
+			},
+			wantContacts: []*people.Person{
+				testPerson("people/c1", "Alice", withGroups("contactGroups/myContacts", "contactGroups/a1")),
+				testPerson("people/c2", "Bob", withGroups("contactGroups/myContacts", "contactGroups/a1", "contactGroups/a1-private")),
+			},
+			wantGroups: []*people.ContactGroup{
+				{ResourceName: "contactGroups/myContacts", Name: "myContacts", GroupType: "SYSTEM_CONTACT_GROUP", MemberCount: 2},
+				{ResourceName: "contactGroups/a1", Name: "Book Club", GroupType: "USER_CONTACT_GROUP", MemberCount: 2},
+				{ResourceName: "contactGroups/a1-private", Name: "Family", GroupType: "USER_CONTACT_GROUP", MemberCount: 1},
+			},
+		},
+		{
+			name: "Contacts joined by email",
+			accounts: []Account{
+				{
+					Name:     "work",
+					Contacts: []*people.Person{testPerson("people/c1", "Alice", withGroups("contactGroups/a1"), withEmails("alice@work.example.com"))},
+					Groups:   []*people.ContactGroup{{ResourceName: "contactGroups/a1", Name: "Colleagues", GroupType: "USER_CONTACT_GROUP"}},
+				},
+				{
+					Name: "private",
+					Contacts: []*people.Person{
+						testPerson("people/c9", "Ali", withGroups("contactGroups/b1"), withEmails("Alice@Work.example.com", "alice@example.com"),
+							func(p *people.Person) { p.Birthdays = []*people.Birthday{{Text: "April 7"}} }),
+					},
+					Groups: []*people.ContactGroup{{ResourceName: "contactGroups/b1", Name: "Friends", GroupType: "USER_CONTACT_GROUP"}},
+				},
+			},
+			wantContacts: []*people.Person{
+				testPerson("people/c1", "Alice", withGroups("contactGroups/a1", "contactGroups/b1"), withEmails("alice@work.example.com", "alice@example.com"),
+					func(p *people.Person) { p.Birthdays = []*people.Birthday{{Text: "April 7"}} }),
+			},
+			wantGroups: []*people.ContactGroup{
+				{ResourceName: "contactGroups/a1", Name: "Colleagues", GroupType: "USER_CONTACT_GROUP", MemberCount: 1},
+				{ResourceName: "contactGroups/b1", Name: "Friends", GroupType: "USER_CONTACT_GROUP", MemberCount: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contacts, groups := Merge(tt.accounts)
+			if diff := cmp.Diff(tt.wantContacts, contacts); diff != "" {
+				t.Errorf("Merge() contacts mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantGroups, groups); diff != "" {
+				t.Errorf("Merge() groups mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package contacts
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/api/people/v1"
+	"gopkg.in/yaml.v2"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
+)
+
+// GroupTagPrefix is the prefix of the tags naming the groups of a person, e.g. contact-group/family.
+const GroupTagPrefix = "contact-group"
+
+// ResourceNameKey is the frontmatter key storing the resource name of the contact a person note belongs to.
+const ResourceNameKey = "resource name"
+
+// ErrOtherContact is returned by UpdateNote if the note belongs to a different contact.
+var ErrOtherContact = errors.New("note belongs to another contact")
+
+// managedKeys are the frontmatter keys written from the contact. They are removed from a note if the contact has
+// no value for them anymore.
+var managedKeys = []string{"name", ResourceNameKey, "emails", "phones", "organization", "job title", "birthday", "groups"}
+
+// keyFields maps the managed keys to the person field they are written from, the resource name is always written.
+var keyFields = map[string]string{
+	"name":         "names",
+	"emails":       "emailAddresses",
+	"phones":       "phoneNumbers",
+	"organization": "organizations",
+	"job title":    "organizations",
+	"birthday":     "birthdays",
+	"groups":       "memberships",
+}
+
+// ManagedKeys returns the managed keys written from the person fields, a comma separated list or "all" as passed to
+// WithPersonFields. Contacts fetched without a field have no values for it, so the keys written from it are neither
+// updated nor removed by UpdateNote and not compared by Reconcile.
+func ManagedKeys(fields string) []string {
+	if strings.TrimSpace(fields) == "all" {
+		return slices.Clone(managedKeys)
+	}
+	fetched := strings.Split(fields, ",")
+	for i := range fetched {
+		fetched[i] = strings.TrimSpace(fetched[i])
+	}
+	return slices.DeleteFunc(slices.Clone(managedKeys), func(key string) bool {
+		field, ok := keyFields[key]
+		return ok && !slices.Contains(fetched, field)
+	})
+}
+
+// Frontmatter returns the frontmatter values of the person note taken from the contact. Empty values are left out.
+// Groups are written as wiki links to notes named like the group.
+func Frontmatter(p *people.Person, groupNames map[string]string) map[string]any {
+	values := map[string]any{
+		"name":          DisplayName(p),
+		ResourceNameKey: p.ResourceName,
+	}
+	if emails := Emails(p); len(emails) > 0 {
+		values["emails"] = emails
+	}
+	if phones := Phones(p); len(phones) > 0 {
+		values["phones"] = phones
+	}
+	if o := Organization(p); o != nil {
+		if o.Name != "" {
+			values["organization"] = o.Name
+		}
+		if o.Title != "" {
+			values["job title"] = o.Title
+		}
+	}
+	if b := Birthday(p); b != "" {
+		values["birthday"] = b
+	}
+	if groups := Groups(p, groupNames); len(groups) > 0 {
+		links := make([]string, 0, len(groups))
+		for _, g := range groups {
+			links = append(links, fmt.Sprintf("[[%s]]", obsidianutils.SanitizeFileName(g)))
+		}
+		values["groups"] = links
+	}
+	return values
+}
+
+// NoteFileName returns the file of the person note in folder, named like the person.
+func NoteFileName(folder string, p *people.Person) (string, error) {
+	name := DisplayName(p)
+	if name == "" {
+		return "", fmt.Errorf("contact %s has neither name nor email address", p.ResourceName)
+	}
+	return obsidianutils.CreateFileName(folder, name, true, time.Time{})
+}
+
+// NewNote returns the content of a new person note for the contact. The note is tagged person and with the groups
+// of the contact.
+func NewNote(p *people.Person, groupNames map[string]string, now time.Time) ([]byte, error) {
+	values := Frontmatter(p, groupNames)
+	values["date created"] = now.Format(time.RFC850)
+	values["date modified"] = now.Format(time.RFC850)
+	values["tags"] = groupTags([]string{"person"}, p, groupNames)
+	values["aliases"] = Aliases(p)
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return fmt.Appendf(nil, "---\n%s---\n\n# %s\n\n## Notes\n", data, DisplayName(p)), nil
+}
+
+// UpdateNote updates the frontmatter of an existing person note with the details of the contact, the body of the
+// note is kept as is. Only the managed keys in keys are written, see ManagedKeys. Group tags are replaced while other
+// tags are kept, missing aliases are added. The note is only written if something changed, which is reported.
+// ErrOtherContact is returned if the note has a different resource name.
+func UpdateNote(fileName string, p *people.Person, groupNames map[string]string, keys []string, now time.Time) (bool, error) {
+	fp := obsidianutils.NewSimpleFrontmatterProcessor(fileName)
+	if current, err := fp.GetValue(ResourceNameKey); err == nil && fmt.Sprint(current) != p.ResourceName {
+		return false, fmt.Errorf("%w: %s has %s %v", ErrOtherContact, fileName, ResourceNameKey, current)
+	}
+
+	values := Frontmatter(p, groupNames)
+	maps.DeleteFunc(values, func(key string, _ any) bool { return !slices.Contains(keys, key) })
+	if slices.Contains(keys, "groups") {
+		tags, _ := fp.GetValue("tags")
+		values["tags"] = groupTags(stringList(tags), p, groupNames)
+	}
+	aliases, _ := fp.GetValue("aliases")
+	values["aliases"] = mergeList(stringList(aliases), Aliases(p))
+
+	changed := false
+	for _, key := range keys {
+		if _, ok := values[key]; ok {
+			continue
+		}
+		if _, err := fp.GetValue(key); err == nil {
+			if err := fp.RemoveValue(key); err != nil {
+				return false, err
+			}
+			changed = true
+		}
+	}
+	for key, value := range values {
+		current, err := fp.GetValue(key)
+		if err == nil && fmt.Sprint(current) == fmt.Sprint(value) {
+			continue
+		}
+		if err := fp.SetValue(key, value); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+	if err := fp.SetValue("date modified", now.Format(time.RFC850)); err != nil {
+		return false, err
+	}
+	data, err := fp.GenerateMarkDownDocument()
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(fileName, data, 0600)
+}
+
+// groupTags returns the tags without group tags followed by the tags of the groups of the person.
+func groupTags(tags []string, p *people.Person, groupNames map[string]string) []string {
+	result := slices.DeleteFunc(slices.Clone(tags), func(t string) bool {
+		return strings.HasPrefix(strings.TrimPrefix(t, "#"), GroupTagPrefix+"/")
+	})
+	for _, g := range Groups(p, groupNames) {
+		result = mergeList(result, []string{Tag(GroupTagPrefix, g)})
+	}
+	return result
+}
+
+// mergeList appends the values missing in list.
+func mergeList(list, values []string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// stringList returns a frontmatter value holding a list or a single value as list of strings.
+func stringList(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				result = append(result, fmt.Sprint(item))
+			}
+		}
+		return result
+	case []string:
+		return v
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	}
+	return []string{fmt.Sprint(value)}
+}
+
+// MarkDeleted flags the person note of a contact deleted in Google Contacts with deleted: true, the note is kept.
+// It reports whether the note was changed.
+func MarkDeleted(fileName string, now time.Time) (bool, error) {
+	fp := obsidianutils.NewSimpleFrontmatterProcessor(fileName)
+	if value, err := fp.GetValue("deleted"); err == nil && value == true {
+		return false, nil
+	}
+	if err := fp.SetValue("deleted", true); err != nil {
+		return false, err
+	}
+	if err := fp.SetValue("date modified", now.Format(time.RFC850)); err != nil {
+		return false, err
+	}
+	data, err := fp.GenerateMarkDownDocument()
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(fileName, data, 0600)
+}
@@ -0,0 +1,203 @@
+package contacts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/people/v1"
+)
+
+func TestNewNote(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	got, err := NewNote(fullPerson(), testGroupNames, now)
+	if err != nil {
+		t.Fatalf("NewNote() error = %v", err)
+	}
+	want := `---
+aliases:
+- Ali
+birthday: --04-07
+date created: Monday, 19-Oct-26 10:00:00 UTC
+date modified: Monday, 19-Oct-26 10:00:00 UTC
+emails:
+- alice@example.com
+groups:
+- '[[Friends]]'
+job title: Engineer
+name: Alice Doe
+organization: ACME
+phones:
+- 0171 123
+resource name: people/c1
+tags:
+- person
+- contact-group/friends
+---
+
+# Alice Doe
+
+## Notes
+`
+	if string(got) != want {
+		t.Errorf("NewNote() = %q, want %q", string(got), want)
+	}
+}
+
+func TestUpdateNote(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		note        string
+		person      func(p *people.Person)
+		fields      string
+		contains    []string
+		missing     []string
+		wantChanged bool
+		wantErr     error
+	}{
+		{
+			name: "Update frontmatter and keep body",
+			note: "---\nname: Alice Doe\nresource name: people/c1\nphones:\n- \"+49 999\"\ntags:\n- person\n- friend\n- contact-group/old\naliases:\n- Al\n---\n\n# Alice\n\nMet at the conference.\n",
+			contains: []string{
+				"phones:\n- 0171 123\n",
+				"tags:\n- person\n- friend\n- contact-group/friends\n",
+				"aliases:\n- Al\n- Ali\n",
+				"date modified: Monday, 19-Oct-26 10:00:00 UTC\n",
+				"---\n\n# Alice\n\nMet at the conference.\n",
+			},
+			missing:     []string{"contact-group/old"},
+			wantChanged: true,
+		},
+		{
+			name:        "Remove values the contact lost",
+			note:        "---\nname: Alice Doe\nresource name: people/c1\nbirthday: --04-07\n---\n",
+			person:      func(p *people.Person) { p.Birthdays = nil },
+			missing:     []string{"birthday"},
+			wantChanged: true,
+		},
+		{
+			name: "Keep values of fields not fetched",
+			note: "---\nname: Alice Doe\nresource name: people/c1\nemails:\n- alice@example.com\nphones:\n- \"+49 999\"\nbirthday: --04-07\ngroups:\n- '[[Friends]]'\ntags:\n- person\n- contact-group/friends\n---\n",
+			person: func(p *people.Person) {
+				*p = people.Person{ResourceName: p.ResourceName, PhoneNumbers: p.PhoneNumbers}
+			},
+			fields: "phoneNumbers",
+			contains: []string{
+				"name: Alice Doe\n",
+				"emails:\n- alice@example.com\n",
+				"phones:\n- 0171 123\n",
+				"birthday: --04-07\n",
+				"groups:\n- '[[Friends]]'\n",
+				"tags:\n- person\n- contact-group/friends\n",
+			},
+			wantChanged: true,
+		},
+		{
+			name:        "Note without frontmatter",
+			note:        "# Alice\n\nHand written.\n",
+			contains:    []string{"resource name: people/c1\n", "---\n# Alice\n\nHand written.\n"},
+			wantChanged: true,
+		},
+		{
+			name:    "Other contact",
+			note:    "---\nresource name: people/c2\n---\n",
+			wantErr: ErrOtherContact,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "Alice Doe.md")
+			if err := os.WriteFile(fileName, []byte(tt.note), 0600); err != nil {
+				t.Fatalf("Failed to write note: %v", err)
+			}
+			p := fullPerson()
+			if tt.person != nil {
+				tt.person(p)
+			}
+			fields := DefaultPersonFields
+			if tt.fields != "" {
+				fields = tt.fields
+			}
+			changed, err := UpdateNote(fileName, p, testGroupNames, ManagedKeys(fields), now)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("UpdateNote() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateNote() error = %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("UpdateNote() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			data, err := os.ReadFile(fileName)
+			if err != nil {
+				t.Fatalf("Failed to read note: %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("UpdateNote() note does not contain %q\nNote: %s", want, data)
+				}
+			}
+			for _, missing := range tt.missing {
+				if strings.Contains(string(data), missing) {
+					t.Errorf("UpdateNote() note contains %q\nNote: %s", missing, data)
+				}
+			}
+
+			changed, err = UpdateNote(fileName, p, testGroupNames, ManagedKeys(fields), now.Add(time.Hour))
+			if err != nil || changed {
+				t.Errorf("UpdateNote() second run = %v, %v, want no change", changed, err)
+			}
+		})
+	}
+}
+
+func TestMarkDeleted(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "Bob.md")
+	if err := os.WriteFile(fileName, []byte("---\nname: Bob\nresource name: people/c2\n---\n\nOld friend.\n"), 0600); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	for i, wantChanged := range []bool{true, false} {
+		changed, err := MarkDeleted(fileName, now)
+		if err != nil || changed != wantChanged {
+			t.Errorf("MarkDeleted() run %d = %v, %v, want %v", i+1, changed, err, wantChanged)
+		}
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("Failed to read note: %v", err)
+	}
+	if !strings.Contains(string(data), "deleted: true\n") || !strings.HasSuffix(string(data), "---\n\nOld friend.\n") {
+		t.Errorf("MarkDeleted() note = %q", data)
+	}
+}
+
+func TestManagedKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields string
+		want   []string
+	}{
+		{name: "Default fields", fields: DefaultPersonFields, want: managedKeys},
+		{name: "All fields", fields: "all", want: managedKeys},
+		{name: "Narrowed fields", fields: "names, organizations", want: []string{"name", ResourceNameKey, "organization", "job title"}},
+		{name: "No field writing a key", fields: "addresses", want: []string{ResourceNameKey}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, ManagedKeys(tt.fields)); diff != "" {
+				t.Errorf("ManagedKeys() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package contacts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/api/people/v1"
+)
+
+// userContactGroup is the group type of groups created by the user, as opposed to system groups like myContacts.
+const userContactGroup = "USER_CONTACT_GROUP"
+
+// DisplayName returns the name of the person, preferring the primary name. Contacts without name are named after
+// their first email address, an empty string is returned if there is neither.
+func DisplayName(p *people.Person) string {
+	for _, n := range p.Names {
+		if n.Metadata != nil && n.Metadata.Primary && n.DisplayName != "" {
+			return n.DisplayName
+		}
+	}
+	for _, n := range p.Names {
+		if n.DisplayName != "" {
+			return n.DisplayName
+		}
+	}
+	if emails := Emails(p); len(emails) > 0 {
+		return emails[0]
+	}
+	return ""
+}
+
+// Aliases returns the other names and the nicknames of the person.
+func Aliases(p *people.Person) []string {
+	name := DisplayName(p)
+	var result []string
+	add := func(value string) {
+		if value != "" && value != name && !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	for _, n := range p.Names {
+		add(n.DisplayName)
+	}
+	for _, n := range p.Nicknames {
+		add(n.Value)
+	}
+	return result
+}
+
+// Emails returns the email addresses of the person.
+func Emails(p *people.Person) []string {
+	var result []string
+	for _, e := range p.EmailAddresses {
+		if e.Value != "" {
+			result = append(result, e.Value)
+		}
+	}
+	return result
+}
+
+// Phones returns the phone numbers of the person as entered.
+func Phones(p *people.Person) []string {
+	var result []string
+	for _, n := range p.PhoneNumbers {
+		if n.Value != "" {
+			result = append(result, n.Value)
+		}
+	}
+	return result
+}
+
+// Organization returns the current or first organization of the person, nil if there is none.
+func Organization(p *people.Person) *people.Organization {
+	for _, o := range p.Organizations {
+		if o.Current {
+			return o
+		}
+	}
+	if len(p.Organizations) > 0 {
+		return p.Organizations[0]
+	}
+	return nil
+}
+
+// Birthday returns the birthday of the person formatted as 2006-01-02, or as --01-02 if the year is unknown. The
+// text of the birthday is returned if there is no date.
+func Birthday(p *people.Person) string {
+	for _, b := range p.Birthdays {
+		if b.Date != nil {
+			return FormatDate(b.Date)
+		}
+		if b.Text != "" {
+			return b.Text
+		}
+	}
+	return ""
+}
+
+// FormatDate formats a date of the People API as 2006-01-02, or as --01-02 if the year is unknown.
+func FormatDate(d *people.Date) string {
+	if d.Year == 0 {
+		return fmt.Sprintf("--%02d-%02d", d.Month, d.Day)
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// GroupNames maps the resource names of the contact groups created by the user to their names. System groups like
+// myContacts are left out, as nearly every contact is a member.
+func GroupNames(groups []*people.ContactGroup) map[string]string {
+	result := make(map[string]string)
+	for _, g := range groups {
+		if g.GroupType != userContactGroup {
+			continue
+		}
+		name := g.FormattedName
+		if name == "" {
+			name = g.Name
+		}
+		result[g.ResourceName] = name
+	}
+	return result
+}
+
+// Groups returns the sorted names of the groups the person is a member of, groups not in groupNames are left out.
+func Groups(p *people.Person, groupNames map[string]string) []string {
+	var result []string
+	for _, m := range p.Memberships {
+		if m.ContactGroupMembership == nil {
+			continue
+		}
+		if name, ok := groupNames[m.ContactGroupMembership.ContactGroupResourceName]; ok && !slices.Contains(result, name) {
+			result = append(result, name)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// Tag returns the name as tag below prefix, lower case with spaces replaced by dashes, e.g. contact-group/book-club.
+func Tag(prefix, name string) string {
+	tag := strings.ToLower(strings.Join(strings.Fields(name), "-"))
+	tag = strings.NewReplacer("#", "", ",", "", "[", "", "]", "").Replace(tag)
+	return prefix + "/" + tag
+}
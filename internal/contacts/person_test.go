@@ -0,0 +1,103 @@
+package contacts
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/people/v1"
+)
+
+func TestDisplayName(t *testing.T) {
+	tests := []struct {
+		name   string
+		person *people.Person
+		want   string
+	}{
+		{
+			name: "Primary name",
+			person: &people.Person{Names: []*people.Name{
+				{DisplayName: "Bob"},
+				{DisplayName: "Robert Smith", Metadata: &people.FieldMetadata{Primary: true}},
+			}},
+			want: "Robert Smith",
+		},
+		{
+			name:   "First name",
+			person: &people.Person{Names: []*people.Name{{DisplayName: "Bob"}, {DisplayName: "Robert"}}},
+			want:   "Bob",
+		},
+		{
+			name:   "Email address",
+			person: &people.Person{EmailAddresses: []*people.EmailAddress{{Value: "bob@example.com"}}},
+			want:   "bob@example.com",
+		},
+		{
+			name:   "Nothing",
+			person: &people.Person{},
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DisplayName(tt.person); got != tt.want {
+				t.Errorf("DisplayName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBirthday(t *testing.T) {
+	tests := []struct {
+		name     string
+		birthday *people.Birthday
+		want     string
+	}{
+		{
+			name:     "Full date",
+			birthday: &people.Birthday{Date: &people.Date{Year: 1980, Month: 4, Day: 7}},
+			want:     "1980-04-07",
+		},
+		{
+			name:     "Without year",
+			birthday: &people.Birthday{Date: &people.Date{Month: 12, Day: 24}},
+			want:     "--12-24",
+		},
+		{
+			name:     "Text",
+			birthday: &people.Birthday{Text: "early May"},
+			want:     "early May",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Birthday(&people.Person{Birthdays: []*people.Birthday{tt.birthday}}); got != tt.want {
+				t.Errorf("Birthday() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupNames(t *testing.T) {
+	groupNames := GroupNames([]*people.ContactGroup{
+		{ResourceName: "contactGroups/myContacts", Name: "myContacts", GroupType: "SYSTEM_CONTACT_GROUP"},
+		{ResourceName: "contactGroups/b", Name: "Book Club", GroupType: userContactGroup},
+		{ResourceName: "contactGroups/a", Name: "Family", FormattedName: "Family", GroupType: userContactGroup},
+	})
+	p := &people.Person{Memberships: []*people.Membership{
+		{ContactGroupMembership: &people.ContactGroupMembership{ContactGroupResourceName: "contactGroups/myContacts"}},
+		{ContactGroupMembership: &people.ContactGroupMembership{ContactGroupResourceName: "contactGroups/b"}},
+		{ContactGroupMembership: &people.ContactGroupMembership{ContactGroupResourceName: "contactGroups/a"}},
+		{DomainMembership: &people.DomainMembership{InViewerDomain: true}},
+	}}
+	if diff := cmp.Diff([]string{"Book Club", "Family"}, Groups(p, groupNames)); diff != "" {
+		t.Errorf("Groups() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTag(t *testing.T) {
+	if got := Tag(GroupTagPrefix, " Book  Club #1"); got != "contact-group/book-club-1" {
+		t.Errorf("Tag() = %q, want %q", got, "contact-group/book-club-1")
+	}
+}
@@ -0,0 +1,301 @@
+package contacts
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/api/people/v1"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
+)
+
+// The ways Reconcile matches contacts to person notes, in the order they are tried.
+const (
+	MatchedByResourceName = "resource name"
+	MatchedByEmail        = "email"
+	MatchedByAlias        = "alias"
+)
+
+// listKeys are the managed keys holding lists, the other managed keys hold a single value.
+var listKeys = []string{"emails", "phones", "groups"}
+
+// Report describes the drift between contacts and the person notes in a folder.
+type Report struct {
+	// MissingNotes are the contacts without person note.
+	MissingNotes []ReportContact `json:"missingNotes"`
+	// OrphanedNotes are the files of the person notes without contact.
+	OrphanedNotes []string `json:"orphanedNotes"`
+	// Changed are the notes with values differing from their contact.
+	Changed []NoteMatch `json:"changed"`
+	// Unchanged is the number of notes matching their contact.
+	Unchanged int `json:"unchanged"`
+}
+
+// ReportContact names a contact in a Report.
+type ReportContact struct {
+	Name         string `json:"name"`
+	ResourceName string `json:"resourceName"`
+}
+
+// NoteMatch is a person note matched to a contact.
+type NoteMatch struct {
+	File         string       `json:"file"`
+	Name         string       `json:"name"`
+	ResourceName string       `json:"resourceName"`
+	MatchedBy    string       `json:"matchedBy"`
+	Differences  []Difference `json:"differences"`
+}
+
+// Difference is a frontmatter value of a person note differing from its contact.
+type Difference struct {
+	Key     string `json:"key"`
+	Note    string `json:"note"`
+	Contact string `json:"contact"`
+	// Conflict is set if taking over the value of the contact would drop a value of the note, e.g. a changed phone
+	// number. Differences without conflict only add values to the note.
+	Conflict bool `json:"conflict"`
+
+	// value is written into the note by Apply.
+	value any
+}
+
+// personNote holds the frontmatter values of a person note used for matching.
+type personNote struct {
+	file         string
+	resourceName string
+	emails       []string
+	aliases      []string
+	values       map[string]any
+}
+
+// Reconcile matches the contacts to the person notes in the folder and reports the differences of the values written
+// from the contacts, see Frontmatter. Only the managed keys in keys are compared, see ManagedKeys. A note is matched
+// by its resource name, else by one of its emails or else by its file name, name or aliases matching the name or an
+// alias of the contact. Notes with the resource name of another contact are only matched by it.
+func Reconcile(folder string, contacts []*people.Person, groupNames map[string]string, keys []string) (Report, error) {
+	notes, err := readPersonNotes(folder)
+	if err != nil {
+		return Report{}, err
+	}
+	var (
+		byResourceName = make(map[string]*personNote)
+		byEmail        = make(map[string]*personNote)
+		byAlias        = make(map[string]*personNote)
+	)
+	for _, n := range notes {
+		if n.resourceName != "" {
+			byResourceName[n.resourceName] = n
+			continue
+		}
+		for _, e := range n.emails {
+			byEmail[strings.ToLower(e)] = n
+		}
+		for _, a := range n.aliases {
+			byAlias[strings.ToLower(a)] = n
+		}
+	}
+
+	report := Report{MissingNotes: []ReportContact{}, OrphanedNotes: []string{}, Changed: []NoteMatch{}}
+	matched := make(map[*personNote]bool)
+	for _, p := range contacts {
+		n, matchedBy := findNote(p, byResourceName, byEmail, byAlias, matched)
+		if n == nil {
+			report.MissingNotes = append(report.MissingNotes, ReportContact{Name: DisplayName(p), ResourceName: p.ResourceName})
+			continue
+		}
+		matched[n] = true
+		differences := compareNote(n, Frontmatter(p, groupNames), keys)
+		if len(differences) == 0 {
+			report.Unchanged++
+			continue
+		}
+		report.Changed = append(report.Changed, NoteMatch{
+			File:         n.file,
+			Name:         DisplayName(p),
+			ResourceName: p.ResourceName,
+			MatchedBy:    matchedBy,
+			Differences:  differences,
+		})
+	}
+	for _, n := range notes {
+		if !matched[n] {
+			report.OrphanedNotes = append(report.OrphanedNotes, n.file)
+		}
+	}
+	slices.SortFunc(report.MissingNotes, func(a, b ReportContact) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ResourceName, b.ResourceName))
+	})
+	slices.SortFunc(report.Changed, func(a, b NoteMatch) int { return cmp.Compare(a.File, b.File) })
+	return report, nil
+}
+
+// Apply writes the differences without conflict into the notes of the report and reports the number of notes
+// changed. Notes matched by email or alias get the resource name of their contact, so they are found by it next time.
+func Apply(report Report, now time.Time) (int, error) {
+	changed := 0
+	for _, m := range report.Changed {
+		fp := obsidianutils.NewSimpleFrontmatterProcessor(m.File)
+		updated := false
+		for _, d := range m.Differences {
+			if d.Conflict {
+				continue
+			}
+			if err := fp.SetValue(d.Key, d.value); err != nil {
+				return changed, err
+			}
+			updated = true
+		}
+		if !updated {
+			continue
+		}
+		if err := fp.SetValue("date modified", now.Format(time.RFC850)); err != nil {
+			return changed, err
+		}
+		data, err := fp.GenerateMarkDownDocument()
+		if err != nil {
+			return changed, err
+		}
+		if err := os.WriteFile(m.File, data, 0600); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// Conflicts returns the number of differences with conflict.
+func (r Report) Conflicts() int {
+	result := 0
+	for _, m := range r.Changed {
+		for _, d := range m.Differences {
+			if d.Conflict {
+				result++
+			}
+		}
+	}
+	return result
+}
+
+// Markdown returns the report as Markdown, notes are written as wiki links.
+func (r Report) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Contact reconciliation\n\n")
+	fmt.Fprintf(&sb, "%d notes up to date, %d changed, %d contacts without note, %d notes without contact\n",
+		r.Unchanged, len(r.Changed), len(r.MissingNotes), len(r.OrphanedNotes))
+
+	sb.WriteString("\n## Contacts without note\n\n")
+	for _, c := range r.MissingNotes {
+		fmt.Fprintf(&sb, "- %s (%s)\n", c.Name, c.ResourceName)
+	}
+	sb.WriteString("\n## Notes without contact\n\n")
+	for _, file := range r.OrphanedNotes {
+		fmt.Fprintf(&sb, "- %s\n", noteLink(file))
+	}
+	sb.WriteString("\n## Changed\n")
+	for _, m := range r.Changed {
+		fmt.Fprintf(&sb, "\n### %s\n\nMatched %s by %s.\n\n", noteLink(m.File), m.Name, m.MatchedBy)
+		sb.WriteString("| Key | Note | Contact | Conflict |\n|-----|------|---------|----------|\n")
+		for _, d := range m.Differences {
+			conflict := "no"
+			if d.Conflict {
+				conflict = "yes"
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", d.Key, tableCell(d.Note), tableCell(d.Contact), conflict)
+		}
+	}
+	return sb.String()
+}
+
+// findNote returns the note of the contact and how it was matched, nil if there is none. Notes already matched to
+// another contact are not returned.
+func findNote(p *people.Person, byResourceName, byEmail, byAlias map[string]*personNote, matched map[*personNote]bool) (*personNote, string) {
+	if n, ok := byResourceName[p.ResourceName]; ok && !matched[n] {
+		return n, MatchedByResourceName
+	}
+	for _, e := range Emails(p) {
+		if n, ok := byEmail[strings.ToLower(e)]; ok && !matched[n] {
+			return n, MatchedByEmail
+		}
+	}
+	for _, a := range append([]string{DisplayName(p)}, Aliases(p)...) {
+		if n, ok := byAlias[strings.ToLower(a)]; ok && !matched[n] {
+			return n, MatchedByAlias
+		}
+	}
+	return nil, ""
+}
+
+// compareNote returns the differences between the keys of the note and the values of the contact.
+func compareNote(n *personNote, values map[string]any, keys []string) []Difference {
+	var result []Difference
+	for _, key := range keys {
+		note := stringList(n.values[key])
+		contact := stringList(values[key])
+		if slices.EqualFunc(note, contact, func(a, b string) bool { return a == b || (key == "emails" && strings.EqualFold(a, b)) }) {
+			continue
+		}
+		conflict := len(note) > 0
+		if slices.Contains(listKeys, key) {
+			// a list without conflict only gets values added
+			conflict = slices.ContainsFunc(note, func(v string) bool {
+				return !slices.ContainsFunc(contact, func(c string) bool { return c == v || (key == "emails" && strings.EqualFold(c, v)) })
+			})
+		}
+		result = append(result, Difference{
+			Key:      key,
+			Note:     strings.Join(note, ", "),
+			Contact:  strings.Join(contact, ", "),
+			Conflict: conflict || len(contact) == 0,
+			value:    values[key],
+		})
+	}
+	return result
+}
+
+// readPersonNotes reads the frontmatter of the notes in the folder.
+func readPersonNotes(folder string) ([]*personNote, error) {
+	var result []*personNote
+	err := filepath.WalkDir(folder, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".md" {
+			return nil
+		}
+		fp := obsidianutils.NewSimpleFrontmatterProcessor(p)
+		n := &personNote{file: p, values: make(map[string]any)}
+		for _, key := range append(slices.Clone(managedKeys), "aliases") {
+			if value, err := fp.GetValue(key); err == nil {
+				n.values[key] = value
+			}
+		}
+		if s, ok := n.values[ResourceNameKey].(string); ok {
+			n.resourceName = s
+		}
+		n.emails = stringList(n.values["emails"])
+		n.aliases = append([]string{strings.TrimSuffix(filepath.Base(p), ".md")}, stringList(n.values["name"])...)
+		n.aliases = append(n.aliases, stringList(n.values["aliases"])...)
+		result = append(result, n)
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// noteLink returns a wiki link to the note.
+func noteLink(file string) string {
+	return "[[" + strings.TrimSuffix(filepath.Base(file), ".md") + "]]"
+}
+
+// tableCell escapes the value for a Markdown table.
+func tableCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+}
@@ -0,0 +1,151 @@
+package contacts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/api/people/v1"
+)
+
+// writeNotes writes the notes to the folder, the keys are the file names.
+func writeNotes(t *testing.T, folder string, notes map[string]string) {
+	t.Helper()
+	for name, content := range notes {
+		if err := os.WriteFile(filepath.Join(folder, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write note: %v", err)
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	folder := t.TempDir()
+	writeNotes(t, folder, map[string]string{
+		"Alice Doe.md": "---\nresource name: people/c1\nname: Alice Doe\nemails:\n- alice@example.com\n" +
+			"phones:\n- +49 999\ngroups:\n- '[[Friends]]'\nbirthday: --04-07\njob title: Engineer\n---\n\n# Alice\n",
+		"Bob.md":   "---\nemails:\n- bob@example.com\n---\n\n# Bob\n",
+		"Carol.md": "---\nname: Carol\n---\n",
+		"Dave.md":  "---\nresource name: people/c9\nname: Dave\n---\n",
+		"Frank.md": "---\nresource name: people/c6\nname: Frank\n---\n",
+	})
+	contacts := []*people.Person{
+		fullPerson(),
+		{
+			ResourceName:   "people/c2",
+			Names:          []*people.Name{{DisplayName: "Bob Builder"}},
+			EmailAddresses: []*people.EmailAddress{{Value: "Bob@example.com"}},
+		},
+		{ResourceName: "people/c3", Names: []*people.Name{{DisplayName: "Caroline"}}, Nicknames: []*people.Nickname{{Value: "Carol"}}},
+		{ResourceName: "people/c5", Names: []*people.Name{{DisplayName: "Eve"}}},
+		{ResourceName: "people/c6", Names: []*people.Name{{DisplayName: "Frank"}}},
+	}
+
+	report, err := Reconcile(folder, contacts, testGroupNames, managedKeys)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	want := Report{
+		MissingNotes:  []ReportContact{{Name: "Eve", ResourceName: "people/c5"}},
+		OrphanedNotes: []string{filepath.Join(folder, "Dave.md")},
+		Changed: []NoteMatch{
+			{
+				File: filepath.Join(folder, "Alice Doe.md"), Name: "Alice Doe", ResourceName: "people/c1",
+				MatchedBy: MatchedByResourceName,
+				Differences: []Difference{
+					{Key: "phones", Note: "+49 999", Contact: "0171 123", Conflict: true},
+					{Key: "organization", Contact: "ACME"},
+				},
+			},
+			{
+				File: filepath.Join(folder, "Bob.md"), Name: "Bob Builder", ResourceName: "people/c2", MatchedBy: MatchedByEmail,
+				Differences: []Difference{
+					{Key: "name", Contact: "Bob Builder"},
+					{Key: ResourceNameKey, Contact: "people/c2"},
+				},
+			},
+			{
+				File: filepath.Join(folder, "Carol.md"), Name: "Caroline", ResourceName: "people/c3", MatchedBy: MatchedByAlias,
+				Differences: []Difference{
+					{Key: "name", Note: "Carol", Contact: "Caroline", Conflict: true},
+					{Key: ResourceNameKey, Contact: "people/c3"},
+				},
+			},
+		},
+		Unchanged: 1,
+	}
+	if diff := cmp.Diff(want, report, cmpopts.IgnoreUnexported(Difference{})); diff != "" {
+		t.Errorf("Reconcile() mismatch (-want +got):\n%s", diff)
+	}
+	if report.Conflicts() != 2 {
+		t.Errorf("Conflicts() = %d, want 2", report.Conflicts())
+	}
+	markdown := report.Markdown()
+	for _, line := range []string{
+		"1 notes up to date, 3 changed, 1 contacts without note, 1 notes without contact",
+		"- Eve (people/c5)",
+		"- [[Dave]]",
+		"### [[Bob]]\n\nMatched Bob Builder by email.",
+		"| phones | +49 999 | 0171 123 | yes |",
+	} {
+		if !strings.Contains(markdown, line) {
+			t.Errorf("Markdown() = %q, want it to contain %q", markdown, line)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	folder := t.TempDir()
+	writeNotes(t, folder, map[string]string{
+		"Alice Doe.md": "---\nname: Alice Doe\nphones:\n- +49 999\nemails:\n- alice@example.com\n---\n\n# Alice\n\nMy notes\n",
+	})
+	report, err := Reconcile(folder, []*people.Person{fullPerson()}, testGroupNames, managedKeys)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	changed, err := Apply(report, time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("Apply() changed %d notes, want 1", changed)
+	}
+	data, err := os.ReadFile(filepath.Join(folder, "Alice Doe.md"))
+	if err != nil {
+		t.Fatalf("Failed to read note: %v", err)
+	}
+	for _, want := range []string{"resource name: people/c1", "organization: ACME", "- +49 999", "My notes", "date modified: Monday, 19-Oct-26"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Apply() wrote %q, want it to contain %q", string(data), want)
+		}
+	}
+	if strings.Contains(string(data), "0171 123") {
+		t.Errorf("Apply() wrote %q, want the conflicting phone number kept", string(data))
+	}
+}
+
+func TestReconcileNarrowedFields(t *testing.T) {
+	folder := t.TempDir()
+	writeNotes(t, folder, map[string]string{
+		"Alice Doe.md": "---\nname: Alice Doe\nresource name: people/c1\nemails:\n- alice@example.com\nphones:\n- +49 999\nbirthday: --04-07\n---\n",
+	})
+	p := fullPerson()
+	*p = people.Person{ResourceName: p.ResourceName, PhoneNumbers: p.PhoneNumbers}
+	report, err := Reconcile(folder, []*people.Person{p}, testGroupNames, ManagedKeys("phoneNumbers"))
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(report.Changed) != 1 {
+		t.Fatalf("Reconcile() changed = %v, want one note", report.Changed)
+	}
+	var keys []string
+	for _, d := range report.Changed[0].Differences {
+		keys = append(keys, d.Key)
+	}
+	if diff := cmp.Diff([]string{"phones"}, keys); diff != "" {
+		t.Errorf("Reconcile() differences mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,112 @@
+package contacts
+
+import (
+	"slices"
+	"strings"
+
+	"google.golang.org/api/people/v1"
+)
+
+// Summary lists the display names of the contacts added, updated and deleted by a sync.
+type Summary struct {
+	Added, Updated, Deleted []string
+
+	// DeletedResourceNames contains the resource names of the deleted contacts.
+	DeletedResourceNames []string
+}
+
+// IsEmpty reports whether nothing changed.
+func (s Summary) IsEmpty() bool {
+	return len(s.Added) == 0 && len(s.Updated) == 0 && len(s.Deleted) == 0
+}
+
+// String returns the summary as one line per kind of change, e.g. "added: Alice, Bob".
+func (s Summary) String() string {
+	var sb strings.Builder
+	for _, c := range []struct {
+		kind  string
+		names []string
+	}{{"added", s.Added}, {"updated", s.Updated}, {"deleted", s.Deleted}} {
+		if len(c.names) > 0 {
+			sb.WriteString(c.kind + ": " + strings.Join(c.names, ", ") + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// deleted records a deleted contact, named after the known contact as the deletion has no name.
+func (s *Summary) deleted(known *people.Person, resourceName string) {
+	name := resourceName
+	if known != nil && DisplayName(known) != "" {
+		name = DisplayName(known)
+	}
+	s.Deleted = append(s.Deleted, name)
+	s.DeletedResourceNames = append(s.DeletedResourceNames, resourceName)
+}
+
+// ApplyChanges applies the changes returned by Changes to the contacts of a previous export. Changed contacts replace
+// the known ones, deleted contacts are removed and new contacts are added at the end.
+func ApplyChanges(contacts, changes []*people.Person) ([]*people.Person, Summary) {
+	var summary Summary
+	result := slices.Clone(contacts)
+	for _, change := range changes {
+		i := slices.IndexFunc(result, func(p *people.Person) bool { return p.ResourceName == change.ResourceName })
+		switch {
+		case isDeleted(change):
+			if i >= 0 {
+				summary.deleted(result[i], change.ResourceName)
+				result = slices.Delete(result, i, i+1)
+			}
+		case i >= 0:
+			result[i] = change
+			summary.Updated = append(summary.Updated, DisplayName(change))
+		default:
+			result = append(result, change)
+			summary.Added = append(summary.Added, DisplayName(change))
+		}
+	}
+	return result, summary
+}
+
+// Compare returns the differences between the contacts of a previous export and the contacts of a full sync, contacts
+// with a different etag count as updated.
+func Compare(previous, current []*people.Person) Summary {
+	var summary Summary
+	known := make(map[string]*people.Person, len(previous))
+	for _, p := range previous {
+		known[p.ResourceName] = p
+	}
+	for _, p := range current {
+		old, ok := known[p.ResourceName]
+		switch {
+		case !ok:
+			summary.Added = append(summary.Added, DisplayName(p))
+		case old.Etag != p.Etag:
+			summary.Updated = append(summary.Updated, DisplayName(p))
+		}
+		delete(known, p.ResourceName)
+	}
+	for _, p := range previous {
+		if _, ok := known[p.ResourceName]; ok {
+			summary.deleted(p, p.ResourceName)
+		}
+	}
+	return summary
+}
+
+// WithoutMetadata returns copies of the contacts without the metadata requested for syncing, so exports look the
+// same whichever way the contacts were fetched.
+func WithoutMetadata(contacts []*people.Person) []*people.Person {
+	result := make([]*people.Person, 0, len(contacts))
+	for _, p := range contacts {
+		c := *p
+		c.Metadata = nil
+		result = append(result, &c)
+	}
+	return result
+}
+
+// isDeleted reports whether a change returned by Changes is a deleted contact.
+func isDeleted(p *people.Person) bool {
+	return p.Metadata != nil && p.Metadata.Deleted
+}
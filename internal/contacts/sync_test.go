@@ -0,0 +1,49 @@
+package contacts
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/people/v1"
+)
+
+func TestApplyChanges(t *testing.T) {
+	previous := []*people.Person{testPerson("people/c1", "Alice", withEtag("a")), testPerson("people/c2", "Bob", withEtag("b")), testPerson("people/c3", "Carol", withEtag("c"))}
+	changes := []*people.Person{
+		{ResourceName: "people/c2", Metadata: &people.PersonMetadata{Deleted: true}},
+		{ResourceName: "people/c9", Metadata: &people.PersonMetadata{Deleted: true}},
+		testPerson("people/c3", "Caroline", withEtag("c2")),
+		testPerson("people/c4", "Dave", withEtag("d")),
+	}
+	got, summary := ApplyChanges(previous, changes)
+	want := []*people.Person{testPerson("people/c1", "Alice", withEtag("a")), testPerson("people/c3", "Caroline", withEtag("c2")), testPerson("people/c4", "Dave", withEtag("d"))}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ApplyChanges() contacts mismatch (-want +got):\n%s", diff)
+	}
+	wantSummary := Summary{Added: []string{"Dave"}, Updated: []string{"Caroline"}, Deleted: []string{"Bob"}, DeletedResourceNames: []string{"people/c2"}}
+	if diff := cmp.Diff(wantSummary, summary); diff != "" {
+		t.Errorf("ApplyChanges() summary mismatch (-want +got):\n%s", diff)
+	}
+	if len(previous) != 3 || previous[2].Etag != "c" {
+		t.Errorf("ApplyChanges() modified the previous contacts")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	previous := []*people.Person{testPerson("people/c1", "Alice", withEtag("a")), testPerson("people/c2", "Bob", withEtag("b")), testPerson("people/c3", "Carol", withEtag("c"))}
+	current := []*people.Person{testPerson("people/c1", "Alice", withEtag("a")), testPerson("people/c3", "Caroline", withEtag("c2")), testPerson("people/c4", "Dave", withEtag("d"))}
+	want := Summary{Added: []string{"Dave"}, Updated: []string{"Caroline"}, Deleted: []string{"Bob"}, DeletedResourceNames: []string{"people/c2"}}
+	if diff := cmp.Diff(want, Compare(previous, current)); diff != "" {
+		t.Errorf("Compare() mismatch (-want +got):\n%s", diff)
+	}
+	if got := Compare(nil, nil); !got.IsEmpty() {
+		t.Errorf("Compare() = %v, want no changes", got)
+	}
+}
+
+func TestSummaryString(t *testing.T) {
+	s := Summary{Added: []string{"Dave", "Eve"}, Deleted: []string{"Bob"}}
+	if got, want := s.String(), "added: Dave, Eve\ndeleted: Bob\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
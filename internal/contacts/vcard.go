@@ -0,0 +1,178 @@
+package contacts
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/api/people/v1"
+)
+
+// vCardEscaper escapes text values of vCard properties (RFC 6350, section 3.4).
+var vCardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+
+// vCardTypes maps the types used by the People API to vCard TYPE parameters, other types are left out.
+var vCardTypes = map[string]string{
+	"home":    "home",
+	"work":    "work",
+	"mobile":  "cell",
+	"main":    "voice",
+	"homeFax": "fax,home",
+	"workFax": "fax,work",
+	"pager":   "pager",
+}
+
+// WriteVCards writes the contacts as vCard 4.0 (RFC 6350). groupNames is used to write the groups as CATEGORIES.
+func WriteVCards(w io.Writer, contacts []*people.Person, groupNames map[string]string) error {
+	for _, p := range contacts {
+		if err := writeVCard(w, p, groupNames); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeVCard writes a single contact as vCard.
+func writeVCard(w io.Writer, p *people.Person, groupNames map[string]string) error {
+	var lines []string
+	add := func(property string, values ...string) {
+		lines = append(lines, property+":"+strings.Join(values, ""))
+	}
+	add("BEGIN", "VCARD")
+	add("VERSION", "4.0")
+	add("UID", vCardEscaper.Replace(p.ResourceName))
+	add("FN", vCardEscaper.Replace(DisplayName(p)))
+	if len(p.Names) > 0 {
+		n := p.Names[0]
+		add("N", strings.Join([]string{
+			vCardEscaper.Replace(n.FamilyName), vCardEscaper.Replace(n.GivenName), vCardEscaper.Replace(n.MiddleName),
+			vCardEscaper.Replace(n.HonorificPrefix), vCardEscaper.Replace(n.HonorificSuffix),
+		}, ";"))
+	}
+	for _, n := range p.Nicknames {
+		add("NICKNAME", vCardEscaper.Replace(n.Value))
+	}
+	for _, e := range p.EmailAddresses {
+		add("EMAIL"+typeParameter(e.Type), vCardEscaper.Replace(e.Value))
+	}
+	for _, t := range p.PhoneNumbers {
+		if t.CanonicalForm != "" {
+			add("TEL;VALUE=uri"+typeParameter(t.Type), "tel:"+t.CanonicalForm)
+		} else {
+			add("TEL;VALUE=text"+typeParameter(t.Type), vCardEscaper.Replace(t.Value))
+		}
+	}
+	for _, a := range p.Addresses {
+		add("ADR"+typeParameter(a.Type)+labelParameter(a), strings.Join([]string{
+			vCardEscaper.Replace(a.PoBox), vCardEscaper.Replace(a.ExtendedAddress), vCardEscaper.Replace(a.StreetAddress),
+			vCardEscaper.Replace(a.City), vCardEscaper.Replace(a.Region), vCardEscaper.Replace(a.PostalCode),
+			vCardEscaper.Replace(a.Country),
+		}, ";"))
+	}
+	if o := Organization(p); o != nil {
+		if o.Name != "" || o.Department != "" {
+			add("ORG", vCardEscaper.Replace(o.Name), ";", vCardEscaper.Replace(o.Department))
+		}
+		if o.Title != "" {
+			add("TITLE", vCardEscaper.Replace(o.Title))
+		}
+	}
+	for _, b := range p.Birthdays {
+		if b.Date != nil {
+			add("BDAY", vCardDate(b.Date))
+			break
+		}
+	}
+	for _, e := range p.Events {
+		if e.Date != nil && e.Type == "anniversary" {
+			add("ANNIVERSARY", vCardDate(e.Date))
+		}
+	}
+	for _, r := range p.Relations {
+		add("RELATED;VALUE=text"+relationType(r.Type), vCardEscaper.Replace(r.Person))
+	}
+	for _, u := range p.Urls {
+		add("URL", u.Value)
+	}
+	for _, photo := range p.Photos {
+		if !photo.Default {
+			add("PHOTO", photo.Url)
+			break
+		}
+	}
+	if len(p.Biographies) > 0 {
+		add("NOTE", vCardEscaper.Replace(p.Biographies[0].Value))
+	}
+	if groups := Groups(p, groupNames); len(groups) > 0 {
+		escaped := make([]string, 0, len(groups))
+		for _, g := range groups {
+			escaped = append(escaped, vCardEscaper.Replace(g))
+		}
+		add("CATEGORIES", strings.Join(escaped, ","))
+	}
+	add("END", "VCARD")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldLine(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typeParameter returns the TYPE parameter for a type of the People API, empty for unknown types.
+func typeParameter(typ string) string {
+	if t, ok := vCardTypes[typ]; ok {
+		return ";TYPE=" + t
+	}
+	return ""
+}
+
+// labelParameter returns the LABEL parameter holding the formatted address, empty if the API did not format it.
+func labelParameter(a *people.Address) string {
+	if a.FormattedValue == "" {
+		return ""
+	}
+	return `;LABEL="` + strings.ReplaceAll(FormatAddress(a), `"`, "'") + `"`
+}
+
+// relationType returns the TYPE parameter of a relation, the People API and vCard mostly use the same names.
+func relationType(typ string) string {
+	switch typ {
+	case "spouse", "child", "parent", "friend":
+		return ";TYPE=" + typ
+	case "brother", "sister":
+		return ";TYPE=sibling"
+	case "partner", "domesticPartner":
+		return ";TYPE=sweetheart"
+	case "manager", "assistant":
+		return ";TYPE=co-worker"
+	}
+	return ""
+}
+
+// vCardDate formats a date as 19800407, or as --0407 if the year is unknown.
+func vCardDate(d *people.Date) string {
+	if d.Year == 0 {
+		return fmt.Sprintf("--%02d%02d", d.Month, d.Day)
+	}
+	return fmt.Sprintf("%04d%02d%02d", d.Year, d.Month, d.Day)
+}
+
+// foldLine ends the line with CRLF, folding it after 75 octets without splitting characters.
+func foldLine(line string) string {
+	var sb strings.Builder
+	width := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if width+size > 75 {
+			sb.WriteString("\r\n ")
+			width = 1
+		}
+		sb.WriteRune(r)
+		width += size
+	}
+	sb.WriteString("\r\n")
+	return sb.String()
+}
@@ -0,0 +1,104 @@
+package contacts
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/people/v1"
+)
+
+func TestWriteVCards(t *testing.T) {
+	tests := []struct {
+		name   string
+		person *people.Person
+		want   []string
+	}{
+		{
+			name:   "All fields",
+			person: fullPerson(),
+			want: []string{
+				"BEGIN:VCARD",
+				"VERSION:4.0",
+				"UID:people/c1",
+				"FN:Alice Doe",
+				"N:Doe;Alice;;;",
+				"NICKNAME:Ali",
+				"EMAIL;TYPE=work:alice@example.com",
+				"TEL;VALUE=uri;TYPE=cell:tel:+49171123",
+				`ADR;TYPE=home;LABEL="Main Street 1, 12345 Town":;;;;;;`,
+				"ADR;TYPE=work:;;Work Road 2;City;;54321;Germany",
+				"ORG:ACME;R&D",
+				"TITLE:Engineer",
+				"BDAY:--0407",
+				"ANNIVERSARY:20100601",
+				"RELATED;VALUE=text;TYPE=spouse:Bob",
+				"URL:https://example.com",
+				"NOTE:Met at the conference",
+				"CATEGORIES:Friends",
+				"END:VCARD",
+			},
+		},
+		{
+			name: "Escaped values",
+			person: &people.Person{
+				ResourceName: "people/c2",
+				Names:        []*people.Name{{DisplayName: "Doe, Bob; Jr."}},
+				Biographies:  []*people.Biography{{Value: "first\nsecond"}},
+			},
+			want: []string{
+				"BEGIN:VCARD",
+				"VERSION:4.0",
+				"UID:people/c2",
+				`FN:Doe\, Bob\; Jr.`,
+				"N:;;;;",
+				`NOTE:first\nsecond`,
+				"END:VCARD",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteVCards(&buf, []*people.Person{tt.person}, map[string]string{"contactGroups/friends": "Friends"}); err != nil {
+				t.Fatalf("WriteVCards() error = %v", err)
+			}
+			if want := strings.Join(tt.want, "\r\n") + "\r\n"; buf.String() != want {
+				t.Errorf("WriteVCards() = %q, want %q", buf.String(), want)
+			}
+		})
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "Short line",
+			line: "FN:Alice",
+			want: "FN:Alice\r\n",
+		},
+		{
+			name: "Long line",
+			line: "NOTE:" + strings.Repeat("a", 80),
+			want: "NOTE:" + strings.Repeat("a", 70) + "\r\n " + strings.Repeat("a", 10) + "\r\n",
+		},
+		{
+			name: "Multibyte characters are not split",
+			line: "NOTE:" + strings.Repeat("a", 69) + "äb",
+			want: "NOTE:" + strings.Repeat("a", 69) + "\r\n äb\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := foldLine(tt.line); got != tt.want {
+				t.Errorf("foldLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package googletest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+// Page returns the page of two items starting at the index passed as page token and the token of the next page, like
+// the APIs page their results.
+func Page[T any](items []T, token string) ([]T, string) {
+	start, _ := strconv.Atoi(token)
+	end := min(start+2, len(items))
+	if end == len(items) {
+		return items[start:end], ""
+	}
+	return items[start:end], strconv.Itoa(end)
+}
+
+// Options starts a server for the fake API and returns the options to create a service using it. The server is
+// closed when the test ends.
+func Options(t *testing.T, api http.Handler) []option.ClientOption {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return []option.ClientOption{option.WithEndpoint(server.URL + "/"), option.WithHTTPClient(server.Client())}
+}
+
+// Error answers the request with the status code and an error body like the APIs send.
+func Error(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = fmt.Fprintf(w, `{"error":{"code":%d,"message":%q}}`, code, message)
+}
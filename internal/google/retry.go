@@ -0,0 +1,55 @@
+package google
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// Retry calls the request until it succeeds, fails with an error that is not worth retrying or the retries are used
+// up. The wait before the first retry is backoff and doubles with every further retry, unless the API asks for a
+// specific wait with a Retry-After header.
+func Retry(ctx context.Context, retries int, backoff time.Duration, request func() error) error {
+	wait := backoff
+	for attempt := 0; ; attempt++ {
+		err := request()
+		if err == nil {
+			return nil
+		}
+		var apiErr *googleapi.Error
+		if !errors.As(err, &apiErr) || !Retryable(apiErr) || attempt >= retries {
+			return err
+		}
+		delay := wait
+		if seconds, err := strconv.Atoi(apiErr.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			delay = time.Duration(seconds) * time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		wait *= 2
+	}
+}
+
+// Retryable reports whether a request failing with the API error may succeed later. Besides HTTP 429 and server
+// errors this is the case for HTTP 403 with the reason rateLimitExceeded or userRateLimitExceeded, which Google APIs
+// use for exceeded quotas.
+func Retryable(apiErr *googleapi.Error) bool {
+	switch {
+	case apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= http.StatusInternalServerError:
+		return true
+	case apiErr.Code == http.StatusForbidden:
+		for _, item := range apiErr.Errors {
+			if item.Reason == "rateLimitExceeded" || item.Reason == "userRateLimitExceeded" {
+				return true
+			}
+		}
+	}
+	return false
+}
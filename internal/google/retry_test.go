@@ -0,0 +1,57 @@
+package google
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		errs         []error
+		retries      int
+		wantErr      bool
+		wantRequests int
+	}{
+		{name: "Success", wantRequests: 1},
+		{name: "Retry on rate limit", errs: []error{&googleapi.Error{Code: http.StatusTooManyRequests}, &googleapi.Error{Code: http.StatusServiceUnavailable}}, retries: 2, wantRequests: 3},
+		{name: "Retries used up", errs: []error{&googleapi.Error{Code: http.StatusInternalServerError}, &googleapi.Error{Code: http.StatusInternalServerError}}, retries: 1, wantErr: true, wantRequests: 2},
+		{name: "Client error is not retried", errs: []error{&googleapi.Error{Code: http.StatusBadRequest}}, retries: 2, wantErr: true, wantRequests: 1},
+		{name: "Retry on exceeded quota", errs: []error{&googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}, &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}}, retries: 2, wantRequests: 3},
+		{name: "Forbidden is not retried", errs: []error{&googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "insufficientPermissions"}}}}, retries: 2, wantErr: true, wantRequests: 1},
+		{name: "Other error is not retried", errs: []error{errors.New("connection refused")}, retries: 2, wantErr: true, wantRequests: 1},
+		{name: "Retry-After is honored", errs: []error{&googleapi.Error{Code: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"0"}}}}, retries: 1, wantRequests: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			err := Retry(context.Background(), tt.retries, time.Millisecond, func() error {
+				requests++
+				if requests <= len(tt.errs) {
+					return tt.errs[requests-1]
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Retry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("Retry() made %d requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := Retry(ctx, 3, time.Hour, func() error { return &googleapi.Error{Code: http.StatusTooManyRequests} })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Retry() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -2,7 +2,12 @@ package internal
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Exists checks if the file or directory at the specified path exists and is accessible. Returns true if the file or directory exists and is accessible, false otherwise.
@@ -17,3 +22,58 @@ func Exists(path string) (bool, error) {
 	// Some other error (e.g., permission issues)
 	return false, err
 }
+
+// LoadLocation returns the timezone with the given name, e.g. Europe/Berlin. An empty name returns the local timezone.
+func LoadLocation(name string) (*time.Location, error) {
+	if strings.TrimSpace(name) == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(strings.TrimSpace(name))
+}
+
+// ResolveDate parses a date in the format 2006-01-02 or a relative offset in days like +1 or -3, which is
+// applied to now. An empty value resolves to now. The result is truncated to the date.
+func ResolveDate(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		value = now.Format(time.DateOnly)
+	}
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+		offset, err := strconv.Atoi(value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		value = now.AddDate(0, 0, offset).Format(time.DateOnly)
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// TimeWindow returns the time window from the day passed as from until the end of the day passed as to, both
+// resolved using ResolveDate. The window starts at now if from is empty. Days start at midnight in loc, so a window
+// spanning a daylight saving change is an hour shorter or longer.
+func TimeWindow(now time.Time, from, to string, loc *time.Location) (time.Time, time.Time, error) {
+	now = now.In(loc)
+	start := now
+	if from != "" {
+		day, err := ResolveDate(from, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid -from: %w", err)
+		}
+		start = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	}
+	day, err := ResolveDate(to, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid -to: %w", err)
+	}
+	end := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, errors.New("-to must not be before -from")
+	}
+	return start, end, nil
+}
+
+// DailyNotePath returns the path of the daily note for the given day inside the daily notes folder, which is
+// organized in year and month directories like 2006/01/2006-01-02.md.
+func DailyNotePath(dailyNoteFolder string, day time.Time) string {
+	return path.Join(dailyNoteFolder, day.Format("2006/01/2006-01-02")+".md")
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeWindow(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the clocks are put forward in the night to 2026-03-29 and back in the night to 2026-10-25
+	spring := time.Date(2026, 3, 28, 15, 0, 0, 0, time.UTC)
+	autumn := time.Date(2026, 10, 24, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		from, to string
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{name: "Starts now", now: spring, to: "+0", wantFrom: "2026-03-28T16:00:00+01:00", wantTo: "2026-03-29T00:00:00+01:00"},
+		{name: "Ends after the daylight saving change", now: spring, from: "-1", to: "+1", wantFrom: "2026-03-27T00:00:00+01:00", wantTo: "2026-03-30T00:00:00+02:00"},
+		{name: "Dates", now: spring, from: "2026-03-29", to: "2026-03-29", wantFrom: "2026-03-29T00:00:00+01:00", wantTo: "2026-03-30T00:00:00+02:00"},
+		{name: "Today follows the timezone", now: autumn, from: "+0", to: "+0", wantFrom: "2026-10-25T00:00:00+02:00", wantTo: "2026-10-26T00:00:00+01:00"},
+		{name: "To before from", now: spring, from: "+2", to: "+1", wantErr: true},
+		{name: "Invalid from", now: spring, from: "tomorrow", to: "+1", wantErr: true},
+		{name: "Invalid to", now: spring, to: "2026-13-01", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := TimeWindow(tt.now, tt.from, tt.to, berlin)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TimeWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := from.Format(time.RFC3339); got != tt.wantFrom {
+				t.Errorf("TimeWindow() from = %s, want %s", got, tt.wantFrom)
+			}
+			if got := to.Format(time.RFC3339); got != tt.wantTo {
+				t.Errorf("TimeWindow() to = %s, want %s", got, tt.wantTo)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package ical
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/apognu/gocal"
+)
+
+// Filter decides which events become notes. The zero value lets all events pass.
+type Filter struct {
+	// Include lets only events with a matching title pass.
+	Include *regexp.Regexp
+	// Exclude skips events with a matching title.
+	Exclude *regexp.Regexp
+	// SkipAllDay skips all-day events.
+	SkipAllDay bool
+	// SkipDeclined skips events declined by one of MyEmails.
+	SkipDeclined bool
+	// MyEmails are the own email addresses.
+	MyEmails []string
+	// MinAttendees skips events with fewer attendees.
+	MinAttendees int
+	// Categories lets only events with one of the categories pass.
+	Categories []string
+	// ExcludeCategories skips events with one of the categories.
+	ExcludeCategories []string
+	// Calendars lets only events of the named calendars pass, see Calendar.
+	Calendars []string
+}
+
+// Skip returns the reason to skip the event, an empty string if it becomes a note.
+func (f Filter) Skip(event gocal.Event) string {
+	switch {
+	case f.Include != nil && !f.Include.MatchString(event.Summary):
+		return "title not included"
+	case f.Exclude != nil && f.Exclude.MatchString(event.Summary):
+		return "title excluded"
+	case f.SkipAllDay && isAllDay(event):
+		return "all-day event"
+	case f.SkipDeclined && f.declined(event):
+		return "declined"
+	case f.MinAttendees > 0 && len(event.Attendees) < f.MinAttendees:
+		return "too few attendees"
+	case len(f.Categories) > 0 && !hasAny(event.Categories, f.Categories):
+		return "category not included"
+	case hasAny(event.Categories, f.ExcludeCategories):
+		return "category excluded"
+	case len(f.Calendars) > 0 && !hasAny([]string{Calendar(event)}, f.Calendars):
+		return "calendar not included"
+	}
+	return ""
+}
+
+// declined reports whether one of our addresses declined the event.
+func (f Filter) declined(event gocal.Event) bool {
+	for _, a := range event.Attendees {
+		if strings.EqualFold(a.Status, "DECLINED") && hasAny([]string{MailAddress(a.Value)}, f.MyEmails) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllDay reports whether the event starts at a date instead of a time.
+func isAllDay(event gocal.Event) bool {
+	return event.RawStart.Params["VALUE"] == "DATE" || len(event.RawStart.Value) == 8
+}
+
+// hasAny reports whether one of the values matches one of the wanted values, ignoring case and surrounding space.
+func hasAny(values, wanted []string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return slices.ContainsFunc(wanted, func(w string) bool {
+			return strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(w))
+		})
+	})
+}
@@ -0,0 +1,83 @@
+package ical
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/apognu/gocal"
+)
+
+// testEvent returns a timed event of the calendar Work with two attendees, changed by the options.
+func testEvent(opts ...func(e *gocal.Event)) gocal.Event {
+	e := gocal.Event{
+		Uid:      "uid@example.com",
+		Summary:  "Weekly sync",
+		RawStart: gocal.RawDate{Params: map[string]string{"TZID": "Europe/Berlin"}, Value: "20260302T100000"},
+		Attendees: []gocal.Attendee{
+			{Cn: "Alice", Value: "mailto:alice@example.com", Status: "ACCEPTED"},
+			{Cn: "Bob", Value: "MAILTO:bob@example.com", Status: "DECLINED"},
+		},
+		Categories:       []string{"Work"},
+		CustomAttributes: map[string]string{hiddenPrefix + "CALENDAR": "Work"},
+	}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return e
+}
+
+func TestFilterSkip(t *testing.T) {
+	allDay := func(e *gocal.Event) {
+		e.RawStart = gocal.RawDate{Params: map[string]string{"VALUE": "DATE"}, Value: "20260302"}
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		event  gocal.Event
+		want   string
+	}{
+		{name: "Zero value lets events pass", event: testEvent()},
+		{name: "Title included", filter: Filter{Include: regexp.MustCompile("(?i)sync")}, event: testEvent()},
+		{name: "Title not included", filter: Filter{Include: regexp.MustCompile("^1:1")}, event: testEvent(), want: "title not included"},
+		{name: "Title excluded", filter: Filter{Exclude: regexp.MustCompile("Weekly")}, event: testEvent(), want: "title excluded"},
+		{name: "All-day event by value", filter: Filter{SkipAllDay: true}, event: testEvent(allDay), want: "all-day event"},
+		{name: "All-day event by date", filter: Filter{SkipAllDay: true}, event: testEvent(func(e *gocal.Event) { e.RawStart = gocal.RawDate{Value: "20260302"} }), want: "all-day event"},
+		{name: "Timed event is not all-day", filter: Filter{SkipAllDay: true}, event: testEvent()},
+		{name: "Declined", filter: Filter{SkipDeclined: true, MyEmails: []string{"Bob@Example.com"}}, event: testEvent(), want: "declined"},
+		{name: "Accepted", filter: Filter{SkipDeclined: true, MyEmails: []string{"alice@example.com"}}, event: testEvent()},
+		{name: "Too few attendees", filter: Filter{MinAttendees: 3}, event: testEvent(), want: "too few attendees"},
+		{name: "Enough attendees", filter: Filter{MinAttendees: 2}, event: testEvent()},
+		{name: "Category included", filter: Filter{Categories: []string{" work "}}, event: testEvent()},
+		{name: "Category not included", filter: Filter{Categories: []string{"Private"}}, event: testEvent(), want: "category not included"},
+		{name: "Category excluded", filter: Filter{ExcludeCategories: []string{"WORK"}}, event: testEvent(), want: "category excluded"},
+		{name: "Calendar included", filter: Filter{Calendars: []string{"work"}}, event: testEvent()},
+		{name: "Calendar not included", filter: Filter{Calendars: []string{"Private"}}, event: testEvent(), want: "calendar not included"},
+		{name: "Title checked first", filter: Filter{Exclude: regexp.MustCompile("Weekly"), SkipAllDay: true}, event: testEvent(allDay), want: "title excluded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Skip(tt.event); got != tt.want {
+				t.Errorf("Skip() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarName(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "Named calendar", data: "BEGIN:VCALENDAR\nX-WR-CALNAME: Work \nBEGIN:VEVENT\nEND:VEVENT\nEND:VCALENDAR\n", want: "Work"},
+		{name: "Unnamed calendar", data: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VEVENT\nEND:VCALENDAR\n"},
+		{name: "Name inside an event is ignored", data: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nX-WR-CALNAME:Event\nEND:VEVENT\nEND:VCALENDAR\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calendarName([]byte(tt.data)); got != tt.want {
+				t.Errorf("calendarName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
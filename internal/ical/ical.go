@@ -0,0 +1,131 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apognu/gocal"
+)
+
+// hiddenPrefix is prepended to the custom attributes used to store the recurrence properties, the calendar and the
+// source of an event. Properties renamed to it are kept by gocal as custom attributes.
+const hiddenPrefix = "X-OBS-UTIL-"
+
+// Parse reads the events of a calendar. The fallback is used as calendar name if the calendar does not name itself
+// (X-WR-CALNAME). All-day events and times without timezone are read in loc. Recurring events are not expanded, use
+// Expand for that.
+func Parse(r io.Reader, fallback string, loc *time.Location) ([]gocal.Event, error) {
+	src, err := hideRecurrence(r)
+	if err != nil {
+		return nil, err
+	}
+	calendar := calendarName(src.Bytes())
+	if calendar == "" {
+		calendar = fallback
+	}
+	c := gocal.NewParser(src)
+	c.SkipBounds = true
+	c.AllDayEventsTZ = loc
+	if err := c.Parse(); err != nil {
+		return nil, err
+	}
+	for i := range c.Events {
+		if isFloating(c.Events[i].RawStart) {
+			start, end := inLocation(*c.Events[i].Start, loc), inLocation(*c.Events[i].End, loc)
+			c.Events[i].Start, c.Events[i].End = &start, &end
+		}
+		if c.Events[i].CustomAttributes == nil {
+			c.Events[i].CustomAttributes = make(map[string]string)
+		}
+		c.Events[i].CustomAttributes[hiddenPrefix+"CALENDAR"] = calendar
+	}
+	return c.Events, nil
+}
+
+// Deduplicate merges events found in several calendars by their key, see Key. The event with the highest sequence
+// number wins, on a tie the one found first. The merged event keeps the source and calendar of the event found
+// first, so its note is written where it was found first.
+func Deduplicate(events []gocal.Event) []gocal.Event {
+	var (
+		result []gocal.Event
+		known  = make(map[string]int)
+	)
+	for _, event := range events {
+		key := Key(event)
+		idx, ok := known[key]
+		if !ok {
+			known[key] = len(result)
+			result = append(result, event)
+			continue
+		}
+		if event.Sequence > result[idx].Sequence {
+			event.CustomAttributes = maps.Clone(event.CustomAttributes)
+			event.CustomAttributes[hiddenPrefix+"SOURCE"] = result[idx].CustomAttributes[hiddenPrefix+"SOURCE"]
+			event.CustomAttributes[hiddenPrefix+"CALENDAR"] = Calendar(result[idx])
+			result[idx] = event
+		}
+	}
+	return result
+}
+
+// Key returns the identifier stored as uid in the frontmatter. Modified occurrences of a recurring event share the
+// uid of the series, so the recurrence id is appended for them.
+func Key(event gocal.Event) string {
+	if event.RecurrenceID == "" {
+		return event.Uid
+	}
+	return event.Uid + "#" + event.RecurrenceID
+}
+
+// IsCancelled reports whether the event was cancelled by the organizer.
+func IsCancelled(event gocal.Event) bool {
+	return strings.EqualFold(event.Status, "CANCELLED")
+}
+
+// Calendar returns the name of the calendar the event was read from.
+func Calendar(event gocal.Event) string {
+	return event.CustomAttributes[hiddenPrefix+"CALENDAR"]
+}
+
+// SetSource stores the index of the source the event was read from in the event.
+func SetSource(event gocal.Event, source int) {
+	event.CustomAttributes[hiddenPrefix+"SOURCE"] = strconv.Itoa(source)
+}
+
+// Source returns the index of the source the event was read from, -1 if it is unknown.
+func Source(event gocal.Event) int {
+	i, err := strconv.Atoi(event.CustomAttributes[hiddenPrefix+"SOURCE"])
+	if err != nil {
+		return -1
+	}
+	return i
+}
+
+// MailAddress removes the mailto: scheme from a calendar user address.
+func MailAddress(value string) string {
+	if len(value) >= len("mailto:") && strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		return value[len("mailto:"):]
+	}
+	return value
+}
+
+// calendarName returns the name of the calendar (X-WR-CALNAME), empty if it has none.
+func calendarName(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.EqualFold(line, "BEGIN:VEVENT") {
+			break
+		}
+		if name, ok := strings.CutPrefix(line, "X-WR-CALNAME:"); ok {
+			return strings.TrimSpace(name)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package ical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDeduplicate(t *testing.T) {
+	type merged struct {
+		Summary  string
+		Start    string
+		Source   int
+		Calendar string
+	}
+	var events []gocal.Event
+	for i, file := range []string{"work.ics", "shared.ics"} {
+		read := readFixture(t, file, time.UTC)
+		for _, e := range read {
+			SetSource(e, i)
+		}
+		events = append(events, read...)
+	}
+
+	var got []merged
+	for _, e := range Deduplicate(events) {
+		got = append(got, merged{Summary: e.Summary, Start: e.Start.Format(time.RFC3339), Source: Source(e), Calendar: Calendar(e)})
+	}
+	want := []merged{
+		// the higher sequence of the second source wins, the event stays in the first source
+		{Summary: "Planning (moved)", Start: "2026-03-10T13:00:00Z", Source: 0, Calendar: "Work"},
+		// the lower sequence of the second source loses
+		{Summary: "Review", Start: "2026-03-11T09:00:00Z", Source: 0, Calendar: "Work"},
+		// on a tie the event read first wins
+		{Summary: "Retro", Start: "2026-03-12T09:00:00Z", Source: 0, Calendar: "Work"},
+		{Summary: "Lunch", Start: "2026-03-13T11:00:00Z", Source: 1, Calendar: "Shared"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Deduplicate() mismatch (-want +got):\n%s", diff)
+	}
+	if got := Source(events[3]); got != 1 {
+		t.Errorf("Deduplicate() changed the source of the event read to %d, want 1", got)
+	}
+}
@@ -0,0 +1,229 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/apognu/gocal/parser"
+
+	"github.com/sascha-andres/obsidian-utils/internal/recurrence"
+)
+
+// Item is an event to create a note for. For series notes Occurrences contains the start times of all occurrences
+// within the time window.
+type Item struct {
+	// Event is the event or the occurrence of a recurring event.
+	Event gocal.Event
+	// Rule is the recurrence rule of recurring events, empty for single events.
+	Rule string
+	// Occurrences are the starts of the occurrences of a series within the time window.
+	Occurrences []time.Time
+}
+
+// recurrenceProperties are the properties hidden from gocal. Otherwise gocal expands recurring events itself, limited
+// to its own time window and ignoring parts of the rule.
+var recurrenceProperties = []string{"RRULE", "EXDATE", "RDATE"}
+
+// hideRecurrence renames the recurrence properties of the calendar to custom attributes. The complete original
+// line is kept as value, numbered as there may be several EXDATE and RDATE lines per event. RECURRENCE-ID is kept
+// and copied to a custom attribute, as gocal drops its parameters.
+func hideRecurrence(r io.Reader) (*bytes.Buffer, error) {
+	var (
+		buf bytes.Buffer
+		n   int
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if hasProperty(line, "RECURRENCE-ID") {
+			n++
+			fmt.Fprintf(&buf, "%sRECURRENCE-ID-%d:%s\n", hiddenPrefix, n, line)
+		}
+		for _, p := range recurrenceProperties {
+			if hasProperty(line, p) {
+				n++
+				line = fmt.Sprintf("%s%s-%d:%s", hiddenPrefix, p, n, line)
+				break
+			}
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	return &buf, scanner.Err()
+}
+
+// hasProperty reports whether the content line is the property, ignoring case.
+func hasProperty(line, property string) bool {
+	return len(line) > len(property) && strings.EqualFold(line[:len(property)], property) &&
+		(line[len(property)] == ':' || line[len(property)] == ';')
+}
+
+// hiddenValues returns the original lines of a hidden recurrence property of the event.
+func hiddenValues(event gocal.Event, property string) []string {
+	var result []string
+	for key, value := range event.CustomAttributes {
+		if strings.HasPrefix(key, hiddenPrefix+property+"-") {
+			result = append(result, value)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// hiddenTimes parses the date lists of the hidden EXDATE, RDATE or RECURRENCE-ID lines of the event using their own
+// parameters.
+func hiddenTimes(event gocal.Event, property string, loc *time.Location) ([]time.Time, error) {
+	var result []time.Time
+	for _, line := range hiddenValues(event, property) {
+		idx := strings.LastIndex(line, ":")
+		_, params := parser.ParseParameters(line[:idx])
+		for key, value := range params {
+			params[key] = strings.Trim(value, `"`)
+		}
+		for _, value := range strings.Split(line[idx+1:], ",") {
+			value, _, _ = strings.Cut(strings.TrimSpace(value), "/")
+			t, err := parseTime(value, params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s of event %q: %w", property, event.Summary, err)
+			}
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// recurrenceID formats the start of an occurrence like the RECURRENCE-ID a modified occurrence would have, so a
+// note keeps its key if the occurrence is modified later on.
+func recurrenceID(event gocal.Event, start time.Time) string {
+	switch {
+	case len(event.RawStart.Value) == 8:
+		return start.Format("20060102")
+	case strings.HasSuffix(event.RawStart.Value, "Z"):
+		return start.UTC().Format("20060102T150405Z")
+	}
+	return start.Format("20060102T150405")
+}
+
+// Expand returns the events read by Parse starting within [from, to). Recurring events are expanded into their
+// occurrences, honoring EXDATE, RDATE and modified occurrences (RECURRENCE-ID). A modified occurrence replaces the
+// occurrence it was moved from, even if it was moved out of the time window. With series only one item per recurring
+// event is returned, starting at its first occurrence and listing all occurrences in the time window. All-day dates
+// and times without timezone are read in loc. Recurring events with a rule that is not supported are logged and
+// skipped, their modified occurrences are kept as single events.
+func Expand(logger *slog.Logger, events []gocal.Event, from, to time.Time, series bool, loc *time.Location) ([]Item, error) {
+	inWindow := func(t time.Time) bool { return !t.Before(from) && t.Before(to) }
+
+	recurring := make(map[string]bool)
+	rules := make(map[int]recurrence.Rule)
+	modified := make(map[string][]time.Time)
+	for i, event := range events {
+		if values := hiddenValues(event, "RRULE"); len(values) > 0 {
+			r, err := recurrence.Parse(values[0][strings.Index(values[0], ":")+1:])
+			if err != nil {
+				logger.Warn("skipping recurring event", "summary", event.Summary, "uid", event.Uid, "err", err)
+				continue
+			}
+			rules[i] = r
+			recurring[event.Uid] = true
+			continue
+		}
+		if event.RecurrenceID != "" {
+			rid, err := hiddenTimes(event, "RECURRENCE-ID", loc)
+			if err != nil {
+				return nil, err
+			}
+			modified[event.Uid] = append(modified[event.Uid], rid...)
+		}
+	}
+
+	var (
+		result []Item
+		folded = make(map[string][]time.Time)
+	)
+	for _, event := range events {
+		if len(hiddenValues(event, "RRULE")) > 0 || !inWindow(*event.Start) {
+			continue
+		}
+		if series && event.RecurrenceID != "" && recurring[event.Uid] {
+			folded[event.Uid] = append(folded[event.Uid], *event.Start)
+			continue
+		}
+		result = append(result, Item{Event: event})
+	}
+
+	for i, event := range events {
+		r, ok := rules[i]
+		if !ok {
+			continue
+		}
+		rule := hiddenValues(event, "RRULE")[0]
+		rule = rule[strings.Index(rule, ":")+1:]
+		excluded, err := hiddenTimes(event, "EXDATE", loc)
+		if err != nil {
+			return nil, err
+		}
+		excluded = append(excluded, modified[event.Uid]...)
+		added, err := hiddenTimes(event, "RDATE", loc)
+		if err != nil {
+			return nil, err
+		}
+
+		var starts []time.Time
+		for _, start := range append(r.Between(*event.Start, from, to), added...) {
+			if inWindow(start) && !slices.ContainsFunc(excluded, start.Equal) {
+				starts = append(starts, start)
+			}
+		}
+		if series {
+			starts = append(starts, folded[event.Uid]...)
+		}
+		slices.SortFunc(starts, func(a, b time.Time) int { return a.Compare(b) })
+		starts = slices.CompactFunc(starts, time.Time.Equal)
+		if len(starts) == 0 {
+			continue
+		}
+
+		duration := event.End.Sub(*event.Start)
+		if series {
+			result = append(result, Item{Event: occurrence(event, starts[0], duration), Rule: rule, Occurrences: starts})
+			continue
+		}
+		for _, start := range starts {
+			o := occurrence(event, start, duration)
+			o.RecurrenceID = recurrenceID(event, start)
+			result = append(result, Item{Event: o, Rule: rule})
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b Item) int { return a.Event.Start.Compare(*b.Event.Start) })
+	return result, nil
+}
+
+// In returns the item with all times converted to loc, so dates, file names and the daily note link follow the local
+// calendar instead of the timezone of the event.
+func (it Item) In(loc *time.Location) Item {
+	start, end := it.Event.Start.In(loc), it.Event.End.In(loc)
+	it.Event.Start, it.Event.End = &start, &end
+	occurrences := make([]time.Time, 0, len(it.Occurrences))
+	for _, o := range it.Occurrences {
+		occurrences = append(occurrences, o.In(loc))
+	}
+	it.Occurrences = occurrences
+	return it
+}
+
+// occurrence returns a copy of the event moved to start.
+func occurrence(event gocal.Event, start time.Time, duration time.Duration) gocal.Event {
+	end := start.Add(duration)
+	event.Start = &start
+	event.End = &end
+	return event
+}
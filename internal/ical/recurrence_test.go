@@ -0,0 +1,124 @@
+package ical
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/google/go-cmp/cmp"
+)
+
+// readFixture parses the calendar in testdata, times without timezone are read in loc.
+func readFixture(t *testing.T, name string, loc *time.Location) []gocal.Event {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	events, err := Parse(f, "", loc)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return events
+}
+
+// expanded describes an item returned by Expand.
+type expanded struct {
+	Summary     string
+	Start       string
+	Key         string
+	Occurrences []string
+}
+
+func TestExpand(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 3, 5, 0, 0, 0, 0, berlin)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, berlin)
+
+	tests := []struct {
+		name   string
+		series bool
+		want   []expanded
+	}{
+		{
+			name: "Occurrences",
+			want: []expanded{
+				{Summary: "Weekly (moved in)", Start: "2026-03-06T10:00:00+01:00", Key: "weekly@example.com#20260302T100000"},
+				{Summary: "Weekly", Start: "2026-03-11T14:00:00+01:00", Key: "weekly@example.com#20260311T140000"},
+				{Summary: "Weekly (moved)", Start: "2026-03-17T13:00:00+01:00", Key: "weekly@example.com#20260316T100000"},
+				{Summary: "Single", Start: "2026-03-20T09:00:00+01:00", Key: "single@example.com"},
+				{Summary: "Weekly", Start: "2026-03-30T10:00:00+02:00", Key: "weekly@example.com#20260330T100000"},
+			},
+		},
+		{
+			name:   "Series",
+			series: true,
+			want: []expanded{
+				{Summary: "Weekly", Start: "2026-03-06T10:00:00+01:00", Key: "weekly@example.com", Occurrences: []string{
+					"2026-03-06T10:00:00+01:00",
+					"2026-03-11T14:00:00+01:00",
+					"2026-03-17T13:00:00+01:00",
+					"2026-03-30T10:00:00+02:00",
+				}},
+				{Summary: "Single", Start: "2026-03-20T09:00:00+01:00", Key: "single@example.com"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// times without timezone are read in UTC, so RECURRENCE-ID must be read using its own TZID
+			events := readFixture(t, "recurring.ics", time.UTC)
+			items, err := Expand(slog.New(slog.NewTextHandler(io.Discard, nil)), events, from, to, tt.series, time.UTC)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			var got []expanded
+			for _, it := range items {
+				it = it.In(berlin)
+				e := expanded{Summary: it.Event.Summary, Start: it.Event.Start.Format(time.RFC3339), Key: Key(it.Event)}
+				for _, o := range it.Occurrences {
+					e.Occurrences = append(e.Occurrences, o.Format(time.RFC3339))
+				}
+				got = append(got, e)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Expand() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExpandUnsupportedRule(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, berlin)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, berlin)
+
+	events := readFixture(t, "unsupported.ics", time.UTC)
+	items, err := Expand(slog.New(slog.NewTextHandler(io.Discard, nil)), events, from, to, false, time.UTC)
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+	var got []expanded
+	for _, it := range items {
+		it = it.In(berlin)
+		got = append(got, expanded{Summary: it.Event.Summary, Start: it.Event.Start.Format(time.RFC3339), Key: Key(it.Event)})
+	}
+	want := []expanded{
+		{Summary: "Weekly", Start: "2026-03-02T10:00:00+01:00", Key: "weekly@example.com#20260302T100000"},
+		{Summary: "Weekly", Start: "2026-03-09T10:00:00+01:00", Key: "weekly@example.com#20260309T100000"},
+		{Summary: "Week number (moved)", Start: "2026-03-13T09:00:00+01:00", Key: "weekno@example.com#20260312T090000"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Expand() mismatch (-want +got):\n%s", diff)
+	}
+}
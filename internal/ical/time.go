@@ -0,0 +1,31 @@
+package ical
+
+import (
+	"strings"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/apognu/gocal/parser"
+)
+
+// isFloating reports whether the value is a time without timezone, which gocal reads in the local timezone.
+func isFloating(raw gocal.RawDate) bool {
+	return raw.Params["TZID"] == "" && raw.Params["VALUE"] != "DATE" && len(raw.Value) > 8 && !strings.HasSuffix(raw.Value, "Z")
+}
+
+// inLocation returns the same wall clock time in loc.
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// parseTime parses a date or time value of the calendar, dates and times without timezone are read in loc.
+func parseTime(value string, params map[string]string, loc *time.Location) (time.Time, error) {
+	t, err := parser.ParseTime(value, params, parser.TimeStart, false, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if isFloating(gocal.RawDate{Params: params, Value: value}) {
+		return inLocation(*t, loc), nil
+	}
+	return *t, nil
+}
@@ -0,0 +1,117 @@
+package ical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apognu/gocal"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		value   string
+		params  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{name: "Floating before the daylight saving change", value: "20260329T013000", want: "2026-03-29T01:30:00+01:00"},
+		{name: "Floating after the daylight saving change", value: "20260329T030000", want: "2026-03-29T03:00:00+02:00"},
+		{name: "UTC", value: "20260329T003000Z", want: "2026-03-29T00:30:00Z"},
+		{name: "TZID", value: "20260329T090000", params: map[string]string{"TZID": "America/New_York"}, want: "2026-03-29T09:00:00-04:00"},
+		{name: "Date", value: "20260329", params: map[string]string{"VALUE": "DATE"}, want: "2026-03-29T00:00:00+01:00"},
+		{name: "Invalid", value: "2026-03-29", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			if params == nil {
+				params = map[string]string{}
+			}
+			got, err := parseTime(tt.value, params, berlin)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Format(time.RFC3339) != tt.want {
+				t.Errorf("parseTime() = %s, want %s", got.Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestIsFloating(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  gocal.RawDate
+		want bool
+	}{
+		{name: "Time without timezone", raw: gocal.RawDate{Value: "20260329T090000"}, want: true},
+		{name: "UTC", raw: gocal.RawDate{Value: "20260329T090000Z"}},
+		{name: "TZID", raw: gocal.RawDate{Params: map[string]string{"TZID": "Europe/Berlin"}, Value: "20260329T090000"}},
+		{name: "Date", raw: gocal.RawDate{Params: map[string]string{"VALUE": "DATE"}, Value: "20260329"}},
+		{name: "Date without value type", raw: gocal.RawDate{Value: "20260329"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFloating(tt.raw); got != tt.want {
+				t.Errorf("isFloating() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimezones(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := readFixture(t, "timezones.ics", berlin)
+	got := make(map[string][2]string)
+	for _, e := range events {
+		it := Item{Event: e}.In(berlin)
+		got[e.Summary] = [2]string{it.Event.Start.Format(time.RFC3339), it.Event.End.Format(time.RFC3339)}
+	}
+	want := map[string][2]string{
+		// times without timezone are read in the timezone passed, not in the local timezone
+		"Floating": {"2026-03-29T09:00:00+02:00", "2026-03-29T10:00:00+02:00"},
+		"New York": {"2026-03-29T15:00:00+02:00", "2026-03-29T16:00:00+02:00"},
+		// the UTC times lie before and after the change, so the note falls on the right day and hour
+		"UTC": {"2026-03-29T01:30:00+01:00", "2026-03-29T03:30:00+02:00"},
+		// gocal ends all-day events a second before midnight
+		"All day": {"2026-03-29T00:00:00+01:00", "2026-03-29T23:59:59+02:00"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestItemIn(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 10, 24, 23, 30, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	it := Item{
+		Event:       gocal.Event{Start: &start, End: &end},
+		Occurrences: []time.Time{start, start.AddDate(0, 0, 7)},
+	}
+	got := it.In(berlin)
+	// the clocks are put back at 03:00, so two hours later it is 02:30
+	want := []string{"2026-10-25T01:30:00+02:00", "2026-10-25T02:30:00+01:00", "2026-10-25T01:30:00+02:00", "2026-11-01T00:30:00+01:00"}
+	gotTimes := []string{got.Event.Start.Format(time.RFC3339), got.Event.End.Format(time.RFC3339)}
+	for _, o := range got.Occurrences {
+		gotTimes = append(gotTimes, o.Format(time.RFC3339))
+	}
+	if diff := cmp.Diff(want, gotTimes); diff != "" {
+		t.Errorf("In() mismatch (-want +got):\n%s", diff)
+	}
+	if it.Event.Start.Location() != time.UTC {
+		t.Errorf("In() changed the location of the original item")
+	}
+}
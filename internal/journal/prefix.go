@@ -0,0 +1,74 @@
+package journal
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultPrefix is the default template for the prefix of journal entries, it renders (15:04) for entries with a
+// time and nothing otherwise.
+const DefaultPrefix = "{{ with .Time }}({{ . }}) {{ end }}"
+
+// PrefixData contains the values available in the entry prefix template.
+type PrefixData struct {
+	// Time is the time of the entry formatted using the time format, empty if the entry has no time.
+	Time string
+
+	// Date is the date of the entry formatted as 2006-01-02.
+	Date string
+
+	// Weekday is the english name of the weekday of the entry.
+	Weekday string
+}
+
+// ValidateTimeFormat reports an error if the go time layout does not contain the 24-hour clock 15:04. Entries are kept
+// in time order by the first HH:MM they contain, see markdown.LaterThan, so other layouts would break the ordering.
+func ValidateTimeFormat(layout string) error {
+	if !strings.Contains(layout, "15:04") {
+		return fmt.Errorf("invalid time format %q, it has to contain 15:04 to keep entries in time order", layout)
+	}
+	return nil
+}
+
+// ResolveTime determines the time of a journal entry for the given day. An explicit time passed as at (15:04) is
+// combined with the day, otherwise the current time is used if the day is today. For other days there is no
+// meaningful time, the start of the day is returned together with false.
+func ResolveTime(day time.Time, at string, loc *time.Location, now time.Time) (time.Time, bool, error) {
+	if at != "" {
+		clock, err := time.Parse("15:04", at)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid -at %q, expected 15:04: %w", at, err)
+		}
+		return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc), true, nil
+	}
+	now = now.In(loc)
+	if now.Format(time.DateOnly) != day.Format(time.DateOnly) {
+		return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc), false, nil
+	}
+	return now, true, nil
+}
+
+// RenderPrefix executes the prefix template for the given entry time. If the entry has no time, .Time is empty and
+// only the date fields are set. An empty template renders no prefix.
+func RenderPrefix(prefix, timeFormat string, entryTime time.Time, hasTime bool) (string, error) {
+	if prefix == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("prefix").Parse(prefix)
+	if err != nil {
+		return "", err
+	}
+	data := PrefixData{
+		Date:    entryTime.Format(time.DateOnly),
+		Weekday: entryTime.Weekday().String(),
+	}
+	if hasTime {
+		data.Time = entryTime.Format(timeFormat)
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	return buf.String(), err
+}
@@ -0,0 +1,119 @@
+package journal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateTimeFormat(t *testing.T) {
+	tests := []struct {
+		layout  string
+		wantErr bool
+	}{
+		{layout: "15:04"},
+		{layout: "15:04:05"},
+		{layout: "Mon 15:04"},
+		{layout: "3:04PM", wantErr: true},
+		{layout: "15h04", wantErr: true},
+		{layout: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			if err := ValidateTimeFormat(tt.layout); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTimeFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		day         time.Time
+		at          string
+		want        time.Time
+		wantHasTime bool
+		wantErr     bool
+	}{
+		{
+			name:        "Today in the timezone",
+			day:         time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+			want:        time.Date(2026, 10, 20, 1, 30, 0, 0, berlin),
+			wantHasTime: true,
+		},
+		{
+			name: "Day mismatch",
+			day:  time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 19, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:        "At on another day",
+			day:         time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			at:          "18:30",
+			want:        time.Date(2026, 10, 19, 18, 30, 0, 0, berlin),
+			wantHasTime: true,
+		},
+		{
+			name:        "At on today",
+			day:         time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+			at:          "07:05",
+			want:        time.Date(2026, 10, 20, 7, 5, 0, 0, berlin),
+			wantHasTime: true,
+		},
+		{
+			name:    "Invalid at",
+			day:     time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+			at:      "25:00",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hasTime, err := ResolveTime(tt.day, tt.at, berlin, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) || hasTime != tt.wantHasTime {
+				t.Errorf("ResolveTime() = %v, %t, want %v, %t", got, hasTime, tt.want, tt.wantHasTime)
+			}
+		})
+	}
+}
+
+func TestRenderPrefix(t *testing.T) {
+	entryTime := time.Date(2026, 10, 19, 18, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		prefix     string
+		timeFormat string
+		hasTime    bool
+		want       string
+		wantErr    bool
+	}{
+		{name: "Default", prefix: DefaultPrefix, timeFormat: "15:04", hasTime: true, want: "(18:30) "},
+		{name: "Default without time", prefix: DefaultPrefix, timeFormat: "15:04", want: ""},
+		{name: "Time format", prefix: DefaultPrefix, timeFormat: "15:04:05", hasTime: true, want: "(18:30:00) "},
+		{name: "Date fields", prefix: "{{ .Weekday }} {{ .Date }} {{ .Time }}: ", timeFormat: "15:04", hasTime: true, want: "Monday 2026-10-19 18:30: "},
+		{name: "Date fields without time", prefix: "{{ .Weekday }} {{ .Date }}{{ with .Time }} {{ . }}{{ end }}: ", timeFormat: "15:04", want: "Monday 2026-10-19: "},
+		{name: "Empty", prefix: "", timeFormat: "15:04", hasTime: true, want: ""},
+		{name: "Invalid template", prefix: "{{ .Time", timeFormat: "15:04", wantErr: true},
+		{name: "Unknown field", prefix: "{{ .Hour }}", timeFormat: "15:04", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderPrefix(tt.prefix, tt.timeFormat, entryTime, tt.hasTime)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderPrefix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("RenderPrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// clockPattern matches the first clock time of a list entry, used to keep entries in time order.
+var clockPattern = regexp.MustCompile(`\b([01]?\d|2[0-3]):([0-5]\d)\b`)
+
+// EntryMinutes returns the minutes since midnight of the first clock time (15:04) in a list entry.
+func EntryMinutes(entry string) (int, bool) {
+	m := clockPattern.FindStringSubmatch(entry)
+	if m == nil {
+		return 0, false
+	}
+	h, _ := strconv.Atoi(m[1])
+	mm, _ := strconv.Atoi(m[2])
+	return h*60 + mm, true
+}
+
+// LaterThan returns a function to be used with AddBulletpoint reporting whether an existing entry has a later
+// clock time than t. Entries without a clock time never count as later, so a new entry goes in front of the
+// first later timed entry.
+func LaterThan(t time.Time) func(string) bool {
+	minutes := t.Hour()*60 + t.Minute()
+	return func(item string) bool {
+		existing, ok := EntryMinutes(item)
+		return ok && existing > minutes
+	}
+}
@@ -0,0 +1,165 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAnchorNotFound is returned by AddBulletpoint if the headline or line to add the bullet point below is missing.
+var ErrAnchorNotFound = errors.New("anchor line not found")
+
+// AddBulletpoint adds bulletPoint to the first unordered list below the headline after. If there is no list, a new
+// one is created. The item is appended to the list unless before is passed, in which case it is inserted in front
+// of the first top level item for which before returns true. before receives the item text without marker.
+// after is either a line of the document or a path of headlines separated by " > ", like "## Health > Food".
+func AddBulletpoint(data []byte, bulletPoint, after string, before func(item string) bool) ([]byte, error) {
+	// Convert to lines for easier manipulation
+	content := string(data)
+	lines := strings.Split(content, "\n")
+
+	trimEq := func(a, b string) bool { return strings.TrimSpace(a) == strings.TrimSpace(b) }
+	isHeadline := func(s string) bool {
+		// A markdown headline starts with one or more '#'
+		s = strings.TrimSpace(s)
+		return strings.HasPrefix(s, "#")
+	}
+	isULItem := func(s string) (bool, string, rune) {
+		// Detect unordered list item of the form: optional spaces + ('-', '*', '+') + space
+		// Returns (ok, indent, marker)
+		if s == "" {
+			return false, "", 0
+		}
+		// Count leading spaces
+		i := 0
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		indent := s[:i]
+		rest := s[i:]
+		if len(rest) < 2 {
+			return false, "", 0
+		}
+		switch rest[0] {
+		case '-', '*', '+':
+			if len(rest) >= 2 && rest[1] == ' ' {
+				return true, indent, rune(rest[0])
+			}
+		}
+		return false, "", 0
+	}
+
+	matchesAnchor := func(s, anchor string) bool {
+		// An anchor without leading '#' matches a headline of any level with that text
+		if trimEq(s, anchor) {
+			return true
+		}
+		anchor = strings.TrimSpace(anchor)
+		return !strings.HasPrefix(anchor, "#") && isHeadline(s) && strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(s), "#")) == anchor
+	}
+
+	// 1) Find the starting point line matching 'after'. A path like "Health > Food" is resolved one anchor after
+	// the other, each one searched below the previous one.
+	start := -1
+	for _, anchor := range strings.Split(after, " > ") {
+		found := -1
+		for i := start + 1; i < len(lines); i++ {
+			if matchesAnchor(lines[i], anchor) {
+				found = i
+				break
+			}
+		}
+		if found == -1 {
+			return nil, fmt.Errorf("%w: %q", ErrAnchorNotFound, after)
+		}
+		start = found
+	}
+
+	// 2) Determine the scan window: from line after 'after' to before the next headline
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if isHeadline(lines[i]) {
+			end = i
+			break
+		}
+	}
+
+	// 3) Search for the first unordered list in [start+1, end)
+	listStart := -1
+	listIndent := ""
+	listMarker := '-'
+	for i := start + 1; i < end; i++ {
+		if ok, indent, marker := isULItem(lines[i]); ok {
+			listStart = i
+			listIndent = indent
+			listMarker = marker
+			break
+		}
+	}
+
+	if listStart != -1 {
+		// 3a) Found a list. Find the last consecutive list item line to append after.
+		listEnd := listStart
+		for i := listStart; i < end; i++ {
+			if ok, _, _ := isULItem(lines[i]); ok {
+				listEnd = i
+				continue
+			}
+			break
+		}
+		// Insert a new list item after listEnd, or in front of the first top level item that should follow it
+		insertion := listEnd + 1
+		if before != nil {
+			for i := listStart; i <= listEnd; i++ {
+				if _, indent, _ := isULItem(lines[i]); indent == listIndent && before(strings.TrimSpace(lines[i])[2:]) {
+					insertion = i
+					break
+				}
+			}
+		}
+		newLine := fmt.Sprintf("%s%c %s", listIndent, listMarker, bulletPoint)
+		// Insert while preserving order
+		lines = append(lines[:insertion], append([]string{newLine}, lines[insertion:]...)...)
+	} else {
+		// 3b) No list found before next headline. Create a new list with the bullet point as first item.
+		// Requirement: ensure exactly one empty line between the starting line and the newly created list.
+		blankStart := start + 1
+		blankEnd := blankStart
+		// Consume existing blank lines right after the anchor (but stop at next headline boundary)
+		for blankEnd < len(lines) && blankEnd < end && strings.TrimSpace(lines[blankEnd]) == "" {
+			blankEnd++
+		}
+		// Ensure there is exactly one blank line between the anchor and the list:
+		// - If there were no blank lines, insert one at blankStart.
+		// - If there were multiple, collapse them to a single one by removing extras.
+		if blankStart >= len(lines) {
+			// Anchor was the last line; just append the one blank line and the new list item.
+			lines = append(lines, "", fmt.Sprintf("- %s", bulletPoint))
+		} else {
+			// We will make sure lines[blankStart] is a blank line and remove any additional blank lines up to blankEnd.
+			if strings.TrimSpace(lines[blankStart]) != "" {
+				// Insert a blank line at blankStart
+				lines = append(lines[:blankStart], append([]string{""}, lines[blankStart:]...)...)
+				// After insertion, the first non-blank shifts by +1
+				blankEnd = blankStart + 1
+				if blankEnd < len(lines) {
+					for blankEnd < len(lines) && blankEnd < end+1 && strings.TrimSpace(lines[blankEnd]) == "" {
+						blankEnd++
+					}
+				}
+			} else {
+				// Collapse multiple blank lines to exactly one
+				if blankEnd > blankStart+1 {
+					lines = append(lines[:blankStart+1], lines[blankEnd:]...)
+					// Adjust end boundary after deletion
+					end -= (blankEnd - (blankStart + 1))
+				}
+			}
+			// Insert the new list item right after the single blank line
+			insertion := blankStart + 1
+			lines = append(lines[:insertion], append([]string{fmt.Sprintf("- %s\n", bulletPoint)}, lines[insertion:]...)...)
+		}
+	}
+
+	return []byte(strings.Join(lines, "\n")), nil
+}
@@ -0,0 +1,97 @@
+package markdown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddBulletpoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		bulletPoint string
+		after       string
+		before      func(string) bool
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "Append to existing list",
+			data:        "## Other stuff\n\n- (09:00) a\n\n## Tasks\n",
+			bulletPoint: "(10:00) b",
+			after:       "## Other stuff",
+			want:        "## Other stuff\n\n- (09:00) a\n- (10:00) b\n\n## Tasks\n",
+		},
+		{
+			name:        "Create new list",
+			data:        "## Other stuff\n## Tasks\n",
+			bulletPoint: "b",
+			after:       "## Other stuff",
+			want:        "## Other stuff\n\n- b\n\n## Tasks\n",
+		},
+		{
+			name:        "Insert in time order",
+			data:        "## Other stuff\n\n- (09:00) a\n  - sub\n- (11:00) c\n- no time\n",
+			bulletPoint: "(10:00) b",
+			after:       "## Other stuff",
+			before:      LaterThan(time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)),
+			want:        "## Other stuff\n\n- (09:00) a\n  - sub\n- (10:00) b\n- (11:00) c\n- no time\n",
+		},
+		{
+			name:        "Append latest entry",
+			data:        "## Other stuff\n\n- (09:00) a\n- no time\n",
+			bulletPoint: "(10:00) b",
+			after:       "## Other stuff",
+			before:      LaterThan(time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)),
+			want:        "## Other stuff\n\n- (09:00) a\n- no time\n- (10:00) b\n",
+		},
+		{
+			name:        "Headline path",
+			data:        "## Work\n\n### Food\n\n## Health\n\n### Food\n",
+			bulletPoint: "apple",
+			after:       "Health > Food",
+			want:        "## Work\n\n### Food\n\n## Health\n\n### Food\n\n- apple\n",
+		},
+		{
+			name:        "Missing anchor",
+			data:        "## Work\n",
+			bulletPoint: "b",
+			after:       "## Other stuff",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AddBulletpoint([]byte(tt.data), tt.bulletPoint, tt.after, tt.before)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddBulletpoint() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("AddBulletpoint() = %q, want %q", string(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryMinutes(t *testing.T) {
+	tests := []struct {
+		entry  string
+		want   int
+		wantOK bool
+	}{
+		{entry: "(09:30) walk", want: 570, wantOK: true},
+		{entry: "Monday 18:05: dinner", want: 1085, wantOK: true},
+		{entry: "no time", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			got, ok := EntryMinutes(tt.entry)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("EntryMinutes() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
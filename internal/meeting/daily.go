@@ -0,0 +1,137 @@
+package meeting
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sascha-andres/obsidian-utils/internal"
+	"github.com/sascha-andres/obsidian-utils/internal/markdown"
+)
+
+// DefaultDailyHeadline is the headline in the daily note below which meetings are linked.
+const DefaultDailyHeadline = "## Today's meetings"
+
+// LinkInDailyNote adds a bullet point like "10:00 [[2026-10-19 Weekly]]" linking the meeting note to the content of a
+// daily note. The entry is placed below headline, which is appended to the note if missing, and kept in time order.
+// If the daily note already links the meeting note, the data is returned unchanged and false.
+func LinkInDailyNote(data []byte, headline, noteFile string, start time.Time) ([]byte, bool, error) {
+	name := NoteName(noteFile)
+	link := fmt.Sprintf("[[%s]]", name)
+	if bytes.Contains(data, []byte(link)) || bytes.Contains(data, []byte("[["+name+"|")) {
+		return data, false, nil
+	}
+	entry := fmt.Sprintf("%s %s", start.Format("15:04"), link)
+	result, err := markdown.AddBulletpoint(data, entry, headline, markdown.LaterThan(start))
+	if errors.Is(err, markdown.ErrAnchorNotFound) {
+		data = append(bytes.TrimRight(data, "\n"), []byte("\n\n"+headline+"\n")...)
+		result, err = markdown.AddBulletpoint(data, entry, headline, markdown.LaterThan(start))
+	}
+	if err != nil {
+		return data, false, err
+	}
+	return result, true, nil
+}
+
+// LinkInDailyNoteFile links the meeting note in the daily note file, see LinkInDailyNote. The daily note has to
+// exist. It reports whether the daily note was changed.
+func LinkInDailyNoteFile(dailyNote, headline, noteFile string, start time.Time) (bool, error) {
+	data, err := os.ReadFile(dailyNote)
+	if err != nil {
+		return false, err
+	}
+	result, changed, err := LinkInDailyNote(data, headline, noteFile, start)
+	if err != nil || !changed {
+		return false, err
+	}
+	return true, os.WriteFile(dailyNote, result, 0640)
+}
+
+// UnlinkInDailyNote removes the bullet points linking the meeting note from the content of a daily note, e.g. after
+// the meeting was moved to another day or time. It reports whether a bullet point was removed.
+func UnlinkInDailyNote(data []byte, noteFile string) ([]byte, bool) {
+	name := NoteName(noteFile)
+	var (
+		result  bytes.Buffer
+		removed bool
+	)
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		item := strings.TrimSpace(line)
+		isItem := strings.HasPrefix(item, "- ") || strings.HasPrefix(item, "* ") || strings.HasPrefix(item, "+ ")
+		if isItem && (strings.Contains(item, "[["+name+"]]") || strings.Contains(item, "[["+name+"|")) {
+			removed = true
+			continue
+		}
+		result.WriteString(line)
+	}
+	return result.Bytes(), removed
+}
+
+// DailyLinks links meeting notes in the daily notes of their day. The methods do nothing on a nil DailyLinks, so
+// callers do not have to check whether linking was requested. A missing daily note is only logged.
+type DailyLinks struct {
+	// Folder contains the daily notes, organized like internal.DailyNotePath expects.
+	Folder string
+	// Headline is the headline in the daily note to link meetings below.
+	Headline string
+	// DryRun prints the daily notes that would be changed instead of changing them.
+	DryRun bool
+	// Logger logs the changed daily notes.
+	Logger *slog.Logger
+}
+
+// Link links the meeting note starting at start in the daily note of its day, see LinkInDailyNote.
+func (d *DailyLinks) Link(noteFile string, start time.Time) error {
+	if d == nil {
+		return nil
+	}
+	dailyNote := internal.DailyNotePath(d.Folder, start)
+	if d.DryRun {
+		fmt.Printf("would link [%s] in [%s]\n", noteFile, dailyNote)
+		return nil
+	}
+	changed, err := LinkInDailyNoteFile(dailyNote, d.Headline, noteFile, start)
+	if errors.Is(err, os.ErrNotExist) {
+		d.Logger.Warn("daily note does not exist, meeting not linked", "file", dailyNote)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if changed {
+		d.Logger.Info("linked meeting in daily note", "file", dailyNote, "meeting", noteFile)
+	}
+	return nil
+}
+
+// Unlink removes the links to the meeting note from the daily note of the day of start, see UnlinkInDailyNote.
+func (d *DailyLinks) Unlink(noteFile string, start time.Time) error {
+	if d == nil {
+		return nil
+	}
+	dailyNote := internal.DailyNotePath(d.Folder, start)
+	if d.DryRun {
+		fmt.Printf("would unlink [%s] in [%s]\n", noteFile, dailyNote)
+		return nil
+	}
+	data, err := os.ReadFile(dailyNote)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	result, removed := UnlinkInDailyNote(data, noteFile)
+	if !removed {
+		return nil
+	}
+	if err := os.WriteFile(dailyNote, result, 0640); err != nil {
+		return err
+	}
+	d.Logger.Info("removed link to moved meeting from daily note", "file", dailyNote, "meeting", noteFile)
+	return nil
+}
@@ -0,0 +1,82 @@
+package meeting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLinkInDailyNote(t *testing.T) {
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		data        string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "Insert in time order",
+			data:        "# Monday\n\n## Today's meetings\n\n- 09:00 [[Standup]]\n- 14:00 [[Review]]\n\n## Notes\n",
+			want:        "# Monday\n\n## Today's meetings\n\n- 09:00 [[Standup]]\n- 10:00 [[2026-10-19 Weekly]]\n- 14:00 [[Review]]\n\n## Notes\n",
+			wantChanged: true,
+		},
+		{
+			name:        "Add missing headline",
+			data:        "# Monday\n\n## Notes\n",
+			want:        "# Monday\n\n## Notes\n\n## Today's meetings\n\n- 10:00 [[2026-10-19 Weekly]]\n",
+			wantChanged: true,
+		},
+		{
+			name: "Already linked",
+			data: "## Today's meetings\n\n- 10:00 [[2026-10-19 Weekly]]\n",
+			want: "## Today's meetings\n\n- 10:00 [[2026-10-19 Weekly]]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := LinkInDailyNote([]byte(tt.data), DefaultDailyHeadline, "/vault/Meetings/2026-10-19 Weekly.md", start)
+			if err != nil {
+				t.Fatalf("LinkInDailyNote() error = %v", err)
+			}
+			if changed != tt.wantChanged || string(got) != tt.want {
+				t.Errorf("LinkInDailyNote() = %q, %v, want %q, %v", string(got), changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestUnlinkInDailyNote(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		want        string
+		wantRemoved bool
+	}{
+		{
+			name:        "Remove link",
+			data:        "## Today's meetings\n\n- 09:00 [[Standup]]\n- 10:00 [[2026-10-19 Weekly]]\n- 14:00 [[Review]]\n",
+			want:        "## Today's meetings\n\n- 09:00 [[Standup]]\n- 14:00 [[Review]]\n",
+			wantRemoved: true,
+		},
+		{
+			name:        "Remove link with alias",
+			data:        "- 10:00 [[2026-10-19 Weekly|Weekly]]\n",
+			want:        "",
+			wantRemoved: true,
+		},
+		{
+			name: "Keep mentions outside of lists",
+			data: "Moved [[2026-10-19 Weekly]] to Tuesday.\n\n- 10:00 [[2026-10-19 Weekly review]]\n",
+			want: "Moved [[2026-10-19 Weekly]] to Tuesday.\n\n- 10:00 [[2026-10-19 Weekly review]]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, removed := UnlinkInDailyNote([]byte(tt.data), "/vault/Meetings/2026-10-19 Weekly.md")
+			if removed != tt.wantRemoved || string(got) != tt.want {
+				t.Errorf("UnlinkInDailyNote() = %q, %v, want %q, %v", string(got), removed, tt.want, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -2,12 +2,35 @@ package meeting
 
 import (
 	"bytes"
+	_ "embed"
 	"errors"
+	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
+
+	obsidianutils "github.com/sascha-andres/obsidian-utils"
 )
 
+// Attendee represents a person taking part in a meeting.
+type Attendee struct {
+
+	// Name is the display name of the attendee, the email address if no name is known.
+	Name string
+
+	// Email is the email address of the attendee.
+	Email string
+
+	// Status is the participation status of the attendee, e.g. ACCEPTED, DECLINED or TENTATIVE.
+	Status string
+
+	// Display is the name as written into the note, a wiki link to the person note if WithAttendeeLinks is used.
+	// It is set by CreateContent.
+	Display string
+}
+
 // TemplateData represents the data necessary for rendering a template. It contains
 // fields for the current time, the appointment time, and the title. This data is used
 // to generate the content of a meeting note by executing a template.
@@ -25,35 +48,75 @@ type TemplateData struct {
 
 	// DayNote is a field of struct type TemplateData. It represents a string used to link to the daily note
 	DayNote string
-}
 
-// The `meetingTemplate` constant is a string that represents a template for generating meeting notes.
-// It uses the Go template syntax and incorporates placeholders for various fields such as the current date,
-// the appointment date, and the meeting title.
-// This template can be used with the `createContent` function to generate the content of a meeting note by
-// providing the necessary data such as the title and appointment time.
-// The resulting content is returned as a string.
-const meetingTemplate = `---
-date created: {{ .Now }}
-date modified: {{ .Now }}
-tags:
-  - meeting
-aliases: 
-date: {{ .Appointment }}
-title: {{ .Title }}
----
+	// End represents the end time of the meeting formatted like Appointment, empty if unknown.
+	End string
+
+	// Duration represents the length of the meeting like 1h30m, empty if the end is unknown.
+	Duration string
+
+	// Attendees contains the people invited to the meeting.
+	Attendees []Attendee
+
+	// Organizer is the person who organized the meeting, nil if unknown.
+	Organizer *Attendee
+
+	// Location is the place or room of the meeting.
+	Location string
+
+	// Description is the agenda or invitation text of the meeting.
+	Description string
 
-[[{{ .DayNote }}]]
+	// Recurrence describes the recurrence of the meeting, e.g. an RRULE like FREQ=WEEKLY;BYDAY=MO.
+	Recurrence string
 
-# Meeting
+	// UID is the unique identifier of the meeting in its source, e.g. the UID of a calendar event.
+	UID string
 
-## Attendees
+	// Tags contains additional tags for the note, e.g. to tell the calendar the meeting comes from.
+	Tags []string
 
-## Notes`
+	// Occurrences contains the start times of all occurrences formatted like Appointment for notes covering a series.
+	Occurrences []string
+
+	// Series is the name of the series index note of a recurring meeting, empty if the note is not part of a series.
+	Series string
+
+	// Previous is the name of the note of the previous occurrence of the series, empty for the first one.
+	Previous string
+
+	// Next is the name of the note of the next occurrence of the series, empty for the last one.
+	Next string
+
+	// ActionItems contains the open tasks carried over from the previous occurrence.
+	ActionItems []string
+}
+
+// meetingTemplate is the default template for generating meeting notes. It uses the Go template syntax and
+// incorporates placeholders for the fields of TemplateData such as the current date, the appointment date, and
+// the meeting title. It is used by CreateContent unless a template is passed using WithTemplate.
+//
+//go:embed meeting.md
+var meetingTemplate string
 
 // Meeting represents a structure for managing and handling meeting-related data using a TemplateData instance.
 type Meeting struct {
-	title string
+	title       string
+	template    string
+	end         time.Time
+	attendees   []Attendee
+	organizer   *Attendee
+	location    string
+	description string
+	recurrence  string
+	uid         string
+	linkPeople  bool
+	occurrences []time.Time
+	tags        []string
+	series      string
+	previous    string
+	next        string
+	actionItems []string
 }
 
 // OptionFunc defines a function type that modifies a Meeting instance or returns an error.
@@ -67,9 +130,123 @@ func WithTitle(title string) OptionFunc {
 	}
 }
 
+// WithTemplate reads the template used to render the meeting note from the given file. If the path is empty,
+// the embedded default template is used.
+func WithTemplate(path string) OptionFunc {
+	return func(m *Meeting) error {
+		if path == "" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		m.template = string(data)
+		return nil
+	}
+}
+
+// WithEnd sets the end time of the meeting, used for End and Duration in the TemplateData.
+func WithEnd(end time.Time) OptionFunc {
+	return func(m *Meeting) error {
+		m.end = end
+		return nil
+	}
+}
+
+// WithAttendees adds the given attendees to the meeting.
+func WithAttendees(attendees ...Attendee) OptionFunc {
+	return func(m *Meeting) error {
+		m.attendees = append(m.attendees, attendees...)
+		return nil
+	}
+}
+
+// WithOrganizer sets the organizer of the meeting.
+func WithOrganizer(organizer Attendee) OptionFunc {
+	return func(m *Meeting) error {
+		m.organizer = &organizer
+		return nil
+	}
+}
+
+// WithAttendeeLinks writes attendees and organizer as wiki links to person notes named like the person.
+func WithAttendeeLinks(link bool) OptionFunc {
+	return func(m *Meeting) error {
+		m.linkPeople = link
+		return nil
+	}
+}
+
+// WithLocation sets the place or room of the meeting.
+func WithLocation(location string) OptionFunc {
+	return func(m *Meeting) error {
+		m.location = location
+		return nil
+	}
+}
+
+// WithDescription sets the agenda or invitation text of the meeting.
+func WithDescription(description string) OptionFunc {
+	return func(m *Meeting) error {
+		m.description = description
+		return nil
+	}
+}
+
+// WithRecurrence sets the description of the recurrence of the meeting.
+func WithRecurrence(recurrence string) OptionFunc {
+	return func(m *Meeting) error {
+		m.recurrence = recurrence
+		return nil
+	}
+}
+
+// WithUID sets the unique identifier of the meeting in its source.
+func WithUID(uid string) OptionFunc {
+	return func(m *Meeting) error {
+		m.uid = uid
+		return nil
+	}
+}
+
+// WithOccurrences sets the start times of all occurrences for a note covering a series of meetings.
+func WithOccurrences(occurrences ...time.Time) OptionFunc {
+	return func(m *Meeting) error {
+		m.occurrences = append(m.occurrences, occurrences...)
+		return nil
+	}
+}
+
+// WithTags adds tags to the note.
+func WithTags(tags ...string) OptionFunc {
+	return func(m *Meeting) error {
+		m.tags = append(m.tags, tags...)
+		return nil
+	}
+}
+
+// WithSeries sets the names of the series index note and of the notes of the previous and next occurrence.
+func WithSeries(index, previous, next string) OptionFunc {
+	return func(m *Meeting) error {
+		m.series = index
+		m.previous = previous
+		m.next = next
+		return nil
+	}
+}
+
+// WithActionItems adds open tasks, e.g. carried over from the previous occurrence.
+func WithActionItems(items ...string) OptionFunc {
+	return func(m *Meeting) error {
+		m.actionItems = append(m.actionItems, items...)
+		return nil
+	}
+}
+
 // NewMeeting initializes and returns a new Meeting instance with the provided options or an error if an option fails.
 func NewMeeting(opts ...OptionFunc) (*Meeting, error) {
-	m := &Meeting{}
+	m := &Meeting{template: meetingTemplate}
 	for _, opt := range opts {
 		if err := opt(m); err != nil {
 			return nil, err
@@ -83,13 +260,15 @@ func NewMeeting(opts ...OptionFunc) (*Meeting, error) {
 
 // CreateContent generates the content for a meeting note using a template.
 // It takes a title and an appointment time as parameters.
+// Templates may use the function quote to write a value as quoted string, e.g. into the frontmatter, and
+// navigation to write the links between the notes of a series, see SeriesNavigation.
 // The template data includes the current time, the appointment date,
 // and the title, which are combined and executed using a template engine.
 // The resulting content is returned as a string.
 // If an error occurs during the parsing or execution of the template,
 // an empty string and the error are returned.
 func (m *Meeting) CreateContent(title string, appointment time.Time) (string, error) {
-	tmpl, err := template.New("m").Parse(meetingTemplate)
+	tmpl, err := template.New("m").Funcs(template.FuncMap{"quote": strconv.Quote, "navigation": SeriesNavigation}).Parse(m.template)
 	if err != nil {
 		return "", err
 	}
@@ -97,12 +276,111 @@ func (m *Meeting) CreateContent(title string, appointment time.Time) (string, er
 	if err != nil {
 		return "", err
 	}
-	td := TemplateData{time.Now().Format(time.RFC850), appointment.Format(time.RFC3339), m.title, appointment.Format("2006-01-02")}
+	td := TemplateData{
+		Now:         time.Now().Format(time.RFC850),
+		Appointment: appointment.Format(time.RFC3339),
+		Title:       m.title,
+		DayNote:     appointment.Format("2006-01-02"),
+		Attendees:   make([]Attendee, 0, len(m.attendees)),
+		Location:    m.location,
+		Description: m.description,
+		Recurrence:  m.recurrence,
+		UID:         m.uid,
+		Tags:        m.tags,
+		Series:      m.series,
+		Previous:    m.previous,
+		Next:        m.next,
+		ActionItems: m.actionItems,
+	}
+	for _, a := range m.attendees {
+		td.Attendees = append(td.Attendees, m.display(a))
+	}
+	for _, o := range m.occurrences {
+		td.Occurrences = append(td.Occurrences, o.Format(time.RFC3339))
+	}
+	if m.organizer != nil {
+		organizer := m.display(*m.organizer)
+		td.Organizer = &organizer
+	}
+	if !m.end.IsZero() {
+		td.End = m.end.Format(time.RFC3339)
+		td.Duration = formatDuration(m.end.Sub(appointment))
+	}
 	var tpl bytes.Buffer
 	err = tmpl.Execute(&tpl, td)
 	return tpl.String(), err
 }
 
+// Frontmatter returns the values the embedded template writes into the frontmatter from the meeting details, used
+// to update existing notes. Unknown values are left out.
+func (m *Meeting) Frontmatter(appointment time.Time) (map[string]any, error) {
+	if err := m.cleanTitle(); err != nil {
+		return nil, err
+	}
+	values := map[string]any{
+		"date":  appointment.Format(time.RFC3339),
+		"title": m.title,
+	}
+	if !m.end.IsZero() {
+		values["end"] = m.end.Format(time.RFC3339)
+	}
+	if m.location != "" {
+		values["location"] = m.location
+	}
+	if m.organizer != nil {
+		values["organizer"] = m.display(*m.organizer).Display
+	}
+	if len(m.attendees) > 0 {
+		attendees := make([]string, 0, len(m.attendees))
+		for _, a := range m.attendees {
+			attendees = append(attendees, m.display(a).Display)
+		}
+		values["attendees"] = attendees
+	}
+	if m.uid != "" {
+		values["uid"] = m.uid
+	}
+	if len(m.occurrences) > 0 {
+		occurrences := make([]string, 0, len(m.occurrences))
+		for _, o := range m.occurrences {
+			occurrences = append(occurrences, o.Format(time.RFC3339))
+		}
+		values["occurrences"] = occurrences
+	}
+	return values, nil
+}
+
+// display sets the Display field of the person, using a wiki link to the person note if requested. The link
+// target uses the same replacements as note file names, the name is kept as alias if it differs.
+func (m *Meeting) display(a Attendee) Attendee {
+	name := a.Name
+	if name == "" {
+		name = a.Email
+	}
+	a.Display = name
+	if m.linkPeople && name != "" {
+		target := obsidianutils.SanitizeFileName(name)
+		if target == name {
+			a.Display = fmt.Sprintf("[[%s]]", name)
+		} else {
+			a.Display = fmt.Sprintf("[[%s|%s]]", target, name)
+		}
+	}
+	return a
+}
+
+// formatDuration formats a duration in whole minutes like 1h30m, 2h or 45m.
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	switch {
+	case minutes%60 == 0 && minutes != 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	case minutes >= 60:
+		return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
 func (m *Meeting) cleanTitle() error {
 	m.title = strings.TrimSpace(m.title)
 	m.title = strings.ReplaceAll(m.title, "\n", "->")
@@ -1,9 +1,13 @@
 package meeting
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestNewMeeting(t *testing.T) {
@@ -129,4 +133,162 @@ func TestCreateContent(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestWithTemplate(t *testing.T) {
+	dir := t.TempDir()
+	templateFile := filepath.Join(dir, "meeting.md")
+	custom := "# {{ .Title }} ({{ .Duration }})\n{{ range .Attendees }}- {{ .Name }} <{{ .Email }}>\n{{ end }}Location: {{ .Location }}\nUID: {{ .UID }}"
+	if err := os.WriteFile(templateFile, []byte(custom), 0600); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	appointment := time.Date(2023, 5, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		path     string
+		opts     []OptionFunc
+		want     string
+		contains []string
+		wantErr  bool
+	}{
+		{
+			name: "Custom template with details",
+			path: templateFile,
+			opts: []OptionFunc{
+				WithEnd(appointment.Add(90 * time.Minute)),
+				WithAttendees(Attendee{Name: "Alice", Email: "alice@example.com"}, Attendee{Name: "Bob", Email: "bob@example.com"}),
+				WithLocation("Room 1"),
+				WithUID("abc@example.com"),
+			},
+			want: "# Weekly (1h30m)\n- Alice <alice@example.com>\n- Bob <bob@example.com>\nLocation: Room 1\nUID: abc@example.com",
+		},
+		{
+			name:     "Empty path uses embedded template",
+			path:     "",
+			contains: []string{"## Attendees", "## Notes"},
+		},
+		{
+			name: "Embedded template with event details",
+			path: "",
+			opts: []OptionFunc{
+				WithEnd(appointment.Add(time.Hour)),
+				WithAttendees(Attendee{Name: "Alice"}, Attendee{Email: "bob@example.com"}, Attendee{Name: "Carol: QA"}),
+				WithOrganizer(Attendee{Name: "Alice"}),
+				WithAttendeeLinks(true),
+				WithLocation("Room: 1"),
+				WithDescription("Agenda"),
+				WithTags("work"),
+			},
+			contains: []string{
+				"tags:\n  - meeting\n  - work\n",
+				"end: 2023-05-15T11:00:00Z\n",
+				"location: \"Room: 1\"\n",
+				"organizer: \"[[Alice]]\"\n",
+				"attendees:\n  - \"[[Alice]]\"\n  - \"[[bob@example.com]]\"\n  - \"[[Carol QA|Carol: QA]]\"\n",
+				"## Attendees\n\n- [[Alice]]\n- [[bob@example.com]]\n- [[Carol QA|Carol: QA]]\n\n## Description\n\nAgenda\n\n## Notes",
+			},
+		},
+		{
+			name:     "Embedded template without event details",
+			path:     "",
+			contains: []string{"title: Weekly\n---", "## Attendees\n\n## Notes"},
+		},
+		{
+			name: "Embedded template with series",
+			path: "",
+			opts: []OptionFunc{
+				WithSeries("Weekly Series", "2023-05-08 Weekly", ""),
+				WithActionItems("- [ ] Send slides", "- [ ] Book room"),
+			},
+			contains: []string{
+				"[[2023-05-15]]\n\n<< [[2023-05-08 Weekly|Previous]] | [[Weekly Series|Series]] >>\n\n# Meeting",
+				"## Action items\n\n- [ ] Send slides\n- [ ] Book room\n\n## Notes",
+			},
+		},
+		{
+			name:    "Missing template file",
+			path:    filepath.Join(dir, "missing.md"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]OptionFunc{WithTitle("Weekly"), WithTemplate(tt.path)}, tt.opts...)
+			m, err := NewMeeting(opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewMeeting() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			content, err := m.CreateContent("Weekly", appointment)
+			if err != nil {
+				t.Fatalf("CreateContent() error = %v", err)
+			}
+			if tt.want != "" && content != tt.want {
+				t.Errorf("CreateContent() = %q, want %q", content, tt.want)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(content, want) {
+					t.Errorf("CreateContent() content does not contain %q\nContent: %s", want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestFrontmatter(t *testing.T) {
+	appointment := time.Date(2023, 5, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		opts []OptionFunc
+		want map[string]any
+	}{
+		{
+			name: "Title and date only",
+			opts: []OptionFunc{WithTitle(" Weekly: Sync ")},
+			want: map[string]any{"date": "2023-05-15T10:00:00Z", "title": "Weekly- Sync"},
+		},
+		{
+			name: "Event details",
+			opts: []OptionFunc{
+				WithTitle("Weekly"),
+				WithEnd(appointment.Add(time.Hour)),
+				WithLocation("Room 1"),
+				WithOrganizer(Attendee{Name: "Alice"}),
+				WithAttendees(Attendee{Name: "Bob"}, Attendee{Email: "carol@example.com"}),
+				WithAttendeeLinks(true),
+				WithUID("abc"),
+				WithOccurrences(appointment, appointment.AddDate(0, 0, 7)),
+			},
+			want: map[string]any{
+				"date":        "2023-05-15T10:00:00Z",
+				"end":         "2023-05-15T11:00:00Z",
+				"title":       "Weekly",
+				"location":    "Room 1",
+				"organizer":   "[[Alice]]",
+				"attendees":   []string{"[[Bob]]", "[[carol@example.com]]"},
+				"uid":         "abc",
+				"occurrences": []string{"2023-05-15T10:00:00Z", "2023-05-22T10:00:00Z"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMeeting(tt.opts...)
+			if err != nil {
+				t.Fatalf("NewMeeting() error = %v", err)
+			}
+			got, err := m.Frontmatter(appointment)
+			if err != nil {
+				t.Fatalf("Frontmatter() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Frontmatter() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package meeting
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sascha-andres/obsidian-utils/internal/markdown"
+	"github.com/sascha-andres/obsidian-utils/internal/tasks"
+)
+
+// SeriesHeadline is the headline in the series index below which the occurrences are linked.
+const SeriesHeadline = "## Occurrences"
+
+// wikiLink matches a wiki link and captures its target.
+var wikiLink = regexp.MustCompile(`\[\[([^\]|#]+)`)
+
+// NoteName returns the name of a note as used in wiki links, the file name without folder and extension.
+func NoteName(noteFile string) string {
+	return strings.TrimSuffix(filepath.Base(noteFile), filepath.Ext(noteFile))
+}
+
+// SeriesNavigation returns the line linking a note of a meeting series to the previous and next occurrence and to
+// the series index, like << [[2026-10-13 Weekly|Previous]] | [[Weekly Series|Series]] | [[2026-10-27 Weekly|Next]] >>.
+// Missing neighbours are left out.
+func SeriesNavigation(previous, index, next string) string {
+	var sb strings.Builder
+	sb.WriteString("<< ")
+	if previous != "" {
+		fmt.Fprintf(&sb, "[[%s|Previous]] | ", previous)
+	}
+	fmt.Fprintf(&sb, "[[%s|Series]]", index)
+	if next != "" {
+		fmt.Fprintf(&sb, " | [[%s|Next]]", next)
+	}
+	sb.WriteString(" >>")
+	return sb.String()
+}
+
+// UpdateSeriesNavigation replaces the navigation line of a note belonging to the series index, see SeriesNavigation.
+// It reports whether the note was changed, notes without navigation line are returned unchanged.
+func UpdateSeriesNavigation(data []byte, previous, index, next string) ([]byte, bool) {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "<< ") || !strings.Contains(line, "[["+index+"|Series]]") {
+			continue
+		}
+		navigation := SeriesNavigation(previous, index, next)
+		if line == navigation {
+			return data, false
+		}
+		lines[i] = navigation
+		return []byte(strings.Join(lines, "\n")), true
+	}
+	return data, false
+}
+
+// SeriesIndexContent returns the content of a new series index note for the meeting title and its recurrence rule.
+func SeriesIndexContent(title, recurrence string) string {
+	var sb strings.Builder
+	sb.WriteString("---\ntags:\n  - meeting-series\n")
+	fmt.Fprintf(&sb, "title: %q\n", title)
+	if recurrence != "" {
+		fmt.Fprintf(&sb, "recurrence: %q\n", recurrence)
+	}
+	fmt.Fprintf(&sb, "---\n\n# %s\n\n%s\n", title, SeriesHeadline)
+	return sb.String()
+}
+
+// SeriesNotes returns the names of the notes linked below SeriesHeadline in the series index.
+func SeriesNotes(data []byte) []string {
+	var (
+		result []string
+		inList bool
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			inList = line == SeriesHeadline
+			continue
+		}
+		if !inList || !strings.HasPrefix(line, "- ") {
+			continue
+		}
+		if m := wikiLink.FindStringSubmatch(line); m != nil {
+			result = append(result, strings.TrimSpace(m[1]))
+		}
+	}
+	return result
+}
+
+// AddToSeriesIndex links the note in the series index below SeriesHeadline, keeping the entries sorted by name so
+// notes with a date prefix are listed in the order of the meetings. Notes already linked are not added again.
+func AddToSeriesIndex(data []byte, noteFile string) ([]byte, bool, error) {
+	name := NoteName(noteFile)
+	if slices.Contains(SeriesNotes(data), name) {
+		return data, false, nil
+	}
+	entry := fmt.Sprintf("[[%s]]", name)
+	result, err := markdown.AddBulletpoint(data, entry, SeriesHeadline, func(item string) bool { return item > entry })
+	if err != nil {
+		return data, false, err
+	}
+	return result, true, nil
+}
+
+// OpenActionItems returns the open tasks of a meeting note, e.g. to carry them over to the next occurrence.
+func OpenActionItems(data []byte) []string {
+	var result []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if t, ok := tasks.Parse(scanner.Text()); ok && t.IsOpen() {
+			result = append(result, strings.TrimSpace(scanner.Text()))
+		}
+	}
+	return result
+}
+
+// MarkCarriedOver cancels the open tasks of a meeting note on date after they were carried over to the next
+// occurrence, so task queries list them only in the next occurrence. It reports whether the note was changed.
+func MarkCarriedOver(data []byte, date time.Time) ([]byte, bool) {
+	lines := strings.Split(string(data), "\n")
+	changed := false
+	for i, line := range lines {
+		if t, ok := tasks.Parse(line); !ok || !t.IsOpen() {
+			continue
+		}
+		cancelled, err := tasks.SetStatus(line, tasks.StatusCancelled, date, tasks.DetectFormat(line, tasks.FormatEmoji))
+		if err != nil {
+			continue
+		}
+		lines[i] = cancelled
+		changed = true
+	}
+	if !changed {
+		return data, false
+	}
+	return []byte(strings.Join(lines, "\n")), true
+}
@@ -0,0 +1,166 @@
+package meeting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSeriesNavigation(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		next     string
+		want     string
+	}{
+		{
+			name:     "Both neighbours",
+			previous: "2026-10-13 Weekly",
+			next:     "2026-10-27 Weekly",
+			want:     "<< [[2026-10-13 Weekly|Previous]] | [[Weekly Series|Series]] | [[2026-10-27 Weekly|Next]] >>",
+		},
+		{
+			name: "First occurrence",
+			next: "2026-10-27 Weekly",
+			want: "<< [[Weekly Series|Series]] | [[2026-10-27 Weekly|Next]] >>",
+		},
+		{
+			name:     "Last occurrence",
+			previous: "2026-10-13 Weekly",
+			want:     "<< [[2026-10-13 Weekly|Previous]] | [[Weekly Series|Series]] >>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SeriesNavigation(tt.previous, "Weekly Series", tt.next); got != tt.want {
+				t.Errorf("SeriesNavigation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateSeriesNavigation(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "Add next occurrence",
+			data:        "[[2026-10-20]]\n\n<< [[2026-10-13 Weekly|Previous]] | [[Weekly Series|Series]] >>\n\n# Meeting\n",
+			want:        "[[2026-10-20]]\n\n<< [[2026-10-13 Weekly|Previous]] | [[Weekly Series|Series]] | [[2026-10-27 Weekly|Next]] >>\n\n# Meeting\n",
+			wantChanged: true,
+		},
+		{
+			name: "Up to date",
+			data: "<< [[2026-10-13 Weekly|Previous]] | [[Weekly Series|Series]] | [[2026-10-27 Weekly|Next]] >>\n",
+			want: "<< [[2026-10-13 Weekly|Previous]] | [[Weekly Series|Series]] | [[2026-10-27 Weekly|Next]] >>\n",
+		},
+		{
+			name: "Other series",
+			data: "<< [[Daily Series|Series]] >>\n",
+			want: "<< [[Daily Series|Series]] >>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := UpdateSeriesNavigation([]byte(tt.data), "2026-10-13 Weekly", "Weekly Series", "2026-10-27 Weekly")
+			if changed != tt.wantChanged || string(got) != tt.want {
+				t.Errorf("UpdateSeriesNavigation() = %q, %v, want %q, %v", string(got), changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestAddToSeriesIndex(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		noteFile    string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "New index",
+			data:        SeriesIndexContent("Weekly", "FREQ=WEEKLY"),
+			noteFile:    "/vault/Meetings/2026-10-20 Weekly.md",
+			want:        "---\ntags:\n  - meeting-series\ntitle: \"Weekly\"\nrecurrence: \"FREQ=WEEKLY\"\n---\n\n# Weekly\n\n## Occurrences\n\n- [[2026-10-20 Weekly]]\n",
+			wantChanged: true,
+		},
+		{
+			name:        "Insert in order",
+			data:        "## Occurrences\n\n- [[2026-10-13 Weekly]]\n- [[2026-10-27 Weekly]]\n",
+			noteFile:    "2026-10-20 Weekly.md",
+			want:        "## Occurrences\n\n- [[2026-10-13 Weekly]]\n- [[2026-10-20 Weekly]]\n- [[2026-10-27 Weekly]]\n",
+			wantChanged: true,
+		},
+		{
+			name:     "Already linked",
+			data:     "## Occurrences\n\n- [[2026-10-20 Weekly]]\n",
+			noteFile: "2026-10-20 Weekly.md",
+			want:     "## Occurrences\n\n- [[2026-10-20 Weekly]]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := AddToSeriesIndex([]byte(tt.data), tt.noteFile)
+			if err != nil {
+				t.Fatalf("AddToSeriesIndex() error = %v", err)
+			}
+			if changed != tt.wantChanged || string(got) != tt.want {
+				t.Errorf("AddToSeriesIndex() = %q, %v, want %q, %v", string(got), changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestSeriesNotes(t *testing.T) {
+	data := "# Weekly\n\nSee [[Team]].\n\n## Occurrences\n\n- [[2026-10-13 Weekly]]\n- [[2026-10-20 Weekly|Kickoff]]\n- no link\n\n## Notes\n\n- [[Other]]\n"
+	want := []string{"2026-10-13 Weekly", "2026-10-20 Weekly"}
+	if diff := cmp.Diff(want, SeriesNotes([]byte(data))); diff != "" {
+		t.Errorf("SeriesNotes() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMarkCarriedOver(t *testing.T) {
+	date := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		data        string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "Cancel open tasks",
+			data:        "## Action items\n\n- [ ] Send slides 📅 2026-10-30\n- [x] Book room\n  - [ ] Ask [[Bob]] [due:: 2026-10-30]\n- plain item\n",
+			want:        "## Action items\n\n- [-] Send slides 📅 2026-10-30 ❌ 2026-10-20\n- [x] Book room\n  - [-] Ask [[Bob]] [due:: 2026-10-30] [cancelled:: 2026-10-20]\n- plain item\n",
+			wantChanged: true,
+		},
+		{
+			name: "No open tasks",
+			data: "- [x] Book room\n",
+			want: "- [x] Book room\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := MarkCarriedOver([]byte(tt.data), date)
+			if changed != tt.wantChanged || string(got) != tt.want {
+				t.Errorf("MarkCarriedOver() = %q, %v, want %q, %v", got, changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestOpenActionItems(t *testing.T) {
+	data := "## Notes\n\n- [ ] Send slides 📅 2026-10-30\n- [x] Book room\n  - [ ] Ask facilities\n- [-] Order pizza\n- plain item\n"
+	want := []string{"- [ ] Send slides 📅 2026-10-30", "- [ ] Ask facilities"}
+	if diff := cmp.Diff(want, OpenActionItems([]byte(data))); diff != "" {
+		t.Errorf("OpenActionItems() mismatch (-want +got):\n%s", diff)
+	}
+}
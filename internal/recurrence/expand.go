@@ -0,0 +1,197 @@
+package recurrence
+
+import (
+	"slices"
+	"time"
+)
+
+// Between returns the occurrences of the rule for a series starting at start that lie within [from, to). The
+// dates are calculated in the timezone of start using calendar arithmetic, so the time of day stays the same across
+// daylight saving changes. Occurrences before from still count towards Count.
+func (r Rule) Between(start, from, to time.Time) []time.Time {
+	until := r.Until
+	if r.untilLocal {
+		until = time.Date(until.Year(), until.Month(), until.Day(), until.Hour(), until.Minute(), until.Second(), 0, start.Location())
+	}
+	var (
+		result []time.Time
+		n      int
+	)
+	for period := 0; period < maxPeriods; period++ {
+		periodStart, candidates := r.period(start, period)
+		if !periodStart.Before(to) || (!until.IsZero() && periodStart.After(until)) {
+			break
+		}
+		for _, c := range candidates {
+			if c.Before(start) {
+				continue
+			}
+			if !c.Before(to) || (!until.IsZero() && c.After(until)) {
+				return result
+			}
+			n++
+			if !c.Before(from) {
+				result = append(result, c)
+			}
+			if r.Count > 0 && n >= r.Count {
+				return result
+			}
+		}
+	}
+	return result
+}
+
+// period returns the first day of the nth period of the rule and the sorted candidates inside it.
+func (r Rule) period(start time.Time, n int) (time.Time, []time.Time) {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	loc := start.Location()
+	hour, minute, second := start.Clock()
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, hour, minute, second, 0, loc)
+	}
+
+	var (
+		first      time.Time
+		candidates []time.Time
+	)
+	switch r.Freq {
+	case Daily:
+		first = day(start.Year(), start.Month(), start.Day()+n*interval)
+		if r.matchesMonth(first) && r.matchesMonthDay(first) && r.matchesWeekday(first) {
+			candidates = append(candidates, first)
+		}
+	case Weekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		first = day(start.Year(), start.Month(), start.Day()-offset+7*n*interval)
+		for i := 0; i < 7; i++ {
+			d := day(first.Year(), first.Month(), first.Day()+i)
+			if len(r.ByDay) == 0 && d.Weekday() != start.Weekday() {
+				continue
+			}
+			if r.matchesMonth(d) && r.matchesWeekday(d) {
+				candidates = append(candidates, d)
+			}
+		}
+	case Monthly:
+		first = day(start.Year(), start.Month()+time.Month(n*interval), 1)
+		if r.matchesMonth(first) {
+			candidates = r.monthDays(first, start.Day(), day)
+		}
+	case Yearly:
+		first = day(start.Year()+n*interval, time.January, 1)
+		switch {
+		case len(r.ByDay) > 0 && len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0:
+			last := day(first.Year(), time.December, 31)
+			candidates = expandWeekdays(r.ByDay, first, last, day)
+		case len(r.ByMonth) == 0:
+			candidates = r.monthDays(day(first.Year(), start.Month(), 1), start.Day(), day)
+		default:
+			for _, m := range r.ByMonth {
+				candidates = append(candidates, r.monthDays(day(first.Year(), m, 1), start.Day(), day)...)
+			}
+		}
+	}
+	slices.SortFunc(candidates, func(a, b time.Time) int { return a.Compare(b) })
+	return first, r.setPositions(slices.CompactFunc(candidates, time.Time.Equal))
+}
+
+// monthDays returns the candidates inside the month starting at first. Without ByMonthDay and ByDay the day of
+// the month of the start is used, months without that day are skipped.
+func (r Rule) monthDays(first time.Time, startDay int, day func(int, time.Month, int) time.Time) []time.Time {
+	last := day(first.Year(), first.Month()+1, 0)
+	var result []time.Time
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, md := range r.ByMonthDay {
+			if md < 0 {
+				md = last.Day() + md + 1
+			}
+			if md < 1 || md > last.Day() {
+				continue
+			}
+			d := day(first.Year(), first.Month(), md)
+			if r.matchesWeekday(d) {
+				result = append(result, d)
+			}
+		}
+	case len(r.ByDay) > 0:
+		result = expandWeekdays(r.ByDay, first, last, day)
+	case startDay <= last.Day():
+		result = append(result, day(first.Year(), first.Month(), startDay))
+	}
+	return result
+}
+
+// expandWeekdays returns the days between first and last matching the weekdays, honoring their ordinals.
+func expandWeekdays(byDay []WeekdayNum, first, last time.Time, day func(int, time.Month, int) time.Time) []time.Time {
+	var result []time.Time
+	for _, wd := range byDay {
+		var matching []time.Time
+		offset := (int(wd.Day) - int(first.Weekday()) + 7) % 7
+		for d := day(first.Year(), first.Month(), first.Day()+offset); !d.After(last); d = day(d.Year(), d.Month(), d.Day()+7) {
+			matching = append(matching, d)
+		}
+		switch {
+		case wd.N == 0:
+			result = append(result, matching...)
+		case wd.N > 0 && wd.N <= len(matching):
+			result = append(result, matching[wd.N-1])
+		case wd.N < 0 && -wd.N <= len(matching):
+			result = append(result, matching[len(matching)+wd.N])
+		}
+	}
+	return result
+}
+
+// setPositions applies BySetPos to the sorted candidates of a period.
+func (r Rule) setPositions(candidates []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return candidates
+	}
+	var result []time.Time
+	for _, pos := range r.BySetPos {
+		switch {
+		case pos > 0 && pos <= len(candidates):
+			result = append(result, candidates[pos-1])
+		case pos < 0 && -pos <= len(candidates):
+			result = append(result, candidates[len(candidates)+pos])
+		}
+	}
+	slices.SortFunc(result, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(result, time.Time.Equal)
+}
+
+// matchesMonth reports whether the month of the day is allowed by ByMonth.
+func (r Rule) matchesMonth(d time.Time) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, d.Month())
+}
+
+// matchesMonthDay reports whether the day is allowed by ByMonthDay.
+func (r Rule) matchesMonthDay(d time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	days := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, d.Location()).Day()
+	for _, md := range r.ByMonthDay {
+		if md == d.Day() || days+md+1 == d.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekday reports whether the weekday of the day is allowed by ByDay, ignoring ordinals.
+func (r Rule) matchesWeekday(d time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if wd.Day == d.Weekday() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package recurrence
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// intervalRules maps the interval shortcuts to recurrence rules.
+var intervalRules = map[string]string{
+	"daily":     "FREQ=DAILY",
+	"weekdays":  "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+	"weekly":    "FREQ=WEEKLY",
+	"bi-weekly": "FREQ=WEEKLY;INTERVAL=2",
+	"monthly":   "FREQ=MONTHLY",
+	"yearly":    "FREQ=YEARLY",
+}
+
+// weekdayCodes are the weekday abbreviations used in recurrence rules.
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Options describe a recurring meeting like the flags of am.
+type Options struct {
+	// Rule is a recurrence rule overriding Interval and Days.
+	Rule string
+	// Interval is a shortcut like weekly or monthly-weekday.
+	Interval string
+	// Days is a comma separated list of weekdays like MO,TH.
+	Days string
+	// Times is the number of meetings, at most.
+	Times int
+	// Until is the date of the last meeting (2006-01-02), at most.
+	Until string
+}
+
+// Build returns the recurrence rule for a series starting at start. The rule is taken from Rule or built from Interval
+// and Days, limited by Times and Until. The interval monthly-weekday repeats on the nth weekday of the month of
+// start, the fifth one becomes the last one.
+func (o Options) Build(start time.Time) (string, error) {
+	rule := strings.TrimPrefix(strings.TrimSpace(o.Rule), "RRULE:")
+	if rule == "" {
+		switch o.Interval {
+		case "monthly-weekday":
+			n := (start.Day()-1)/7 + 1
+			if n == 5 {
+				n = -1
+			}
+			rule = fmt.Sprintf("FREQ=MONTHLY;BYDAY=%d%s", n, weekdayCodes[start.Weekday()])
+		default:
+			var ok bool
+			if rule, ok = intervalRules[o.Interval]; !ok {
+				return "", fmt.Errorf("invalid interval %q", o.Interval)
+			}
+		}
+		if o.Days != "" {
+			if strings.Contains(rule, "BYDAY") {
+				return "", fmt.Errorf("-days can not be combined with interval %q", o.Interval)
+			}
+			rule += ";BYDAY=" + strings.ToUpper(strings.ReplaceAll(o.Days, " ", ""))
+		}
+	}
+	if o.Times > 1 && !strings.Contains(rule, "COUNT=") {
+		rule += fmt.Sprintf(";COUNT=%d", o.Times)
+	}
+	if o.Until != "" && !strings.Contains(rule, "UNTIL=") {
+		u, err := time.Parse(time.DateOnly, o.Until)
+		if err != nil {
+			return "", fmt.Errorf("invalid -until %q, expected 2006-01-02: %w", o.Until, err)
+		}
+		rule += ";UNTIL=" + u.Format("20060102")
+	}
+	if !strings.Contains(rule, "COUNT=") && !strings.Contains(rule, "UNTIL=") {
+		return "", errors.New("recurring meetings need -times greater than 1 or -until")
+	}
+	return rule, nil
+}
+
+// Occurrences returns the starts of all meetings of the rule for a series starting at start. Holidays, dates
+// formatted as 2006-01-02, are skipped. They still count towards the COUNT of the rule.
+func Occurrences(rule string, start time.Time, holidays map[string]bool) ([]time.Time, error) {
+	r, err := Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+	end := start.AddDate(100, 0, 0)
+	if !r.Until.IsZero() {
+		end = r.Until.AddDate(0, 0, 2)
+	}
+	var result []time.Time
+	for _, o := range r.Between(start, start, end) {
+		if holidays[o.Format(time.DateOnly)] {
+			continue
+		}
+		result = append(result, o)
+	}
+	return result, nil
+}
+
+// ReadHolidays reads a file listing one date (2006-01-02) per line, optionally followed by a name. Empty lines and
+// lines starting with # are ignored. No file name returns no holidays.
+func ReadHolidays(fileName string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	if fileName == "" {
+		return result, nil
+	}
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		date, _, _ := strings.Cut(text, " ")
+		d, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid date %q", fileName, line, date)
+		}
+		result[d.Format(time.DateOnly)] = true
+	}
+	return result, scanner.Err()
+}
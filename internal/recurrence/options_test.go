@@ -0,0 +1,134 @@
+package recurrence
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOptionsBuild(t *testing.T) {
+	// a Tuesday
+	start := time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		options Options
+		start   time.Time
+		want    string
+		wantErr bool
+	}{
+		{name: "Interval", options: Options{Interval: "weekly", Times: 4}, want: "FREQ=WEEKLY;COUNT=4"},
+		{name: "Weekdays", options: Options{Interval: "weekdays", Until: "2026-03-31"}, want: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;UNTIL=20260331"},
+		{name: "Bi-weekly with days", options: Options{Interval: "bi-weekly", Days: "mo, th", Times: 6}, want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=6"},
+		{name: "Times and until", options: Options{Interval: "daily", Times: 10, Until: "2026-03-05"}, want: "FREQ=DAILY;COUNT=10;UNTIL=20260305"},
+		{name: "Monthly weekday", options: Options{Interval: "monthly-weekday", Times: 3}, want: "FREQ=MONTHLY;BYDAY=1TU;COUNT=3"},
+		{name: "Monthly weekday in the fourth week", options: Options{Interval: "monthly-weekday", Times: 3}, start: time.Date(2026, 3, 24, 10, 0, 0, 0, time.UTC), want: "FREQ=MONTHLY;BYDAY=4TU;COUNT=3"},
+		{name: "Fifth weekday becomes the last one", options: Options{Interval: "monthly-weekday", Times: 3}, start: time.Date(2026, 3, 31, 10, 0, 0, 0, time.UTC), want: "FREQ=MONTHLY;BYDAY=-1TU;COUNT=3"},
+		{name: "Rule overrides interval and days", options: Options{Rule: "RRULE:FREQ=MONTHLY;BYDAY=-1FR", Interval: "weekly", Days: "MO", Times: 2}, want: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=2"},
+		{name: "Rule with count keeps it", options: Options{Rule: "FREQ=DAILY;COUNT=3", Times: 5}, want: "FREQ=DAILY;COUNT=3"},
+		{name: "Rule with until keeps it", options: Options{Rule: "FREQ=DAILY;UNTIL=20260310", Until: "2026-04-01"}, want: "FREQ=DAILY;UNTIL=20260310"},
+		{name: "Days with interval using days", options: Options{Interval: "weekdays", Days: "MO", Times: 2}, wantErr: true},
+		{name: "Unknown interval", options: Options{Interval: "hourly", Times: 2}, wantErr: true},
+		{name: "Invalid until", options: Options{Interval: "daily", Until: "31.03.2026"}, wantErr: true},
+		{name: "Endless", options: Options{Interval: "weekly", Times: 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := start
+			if !tt.start.IsZero() {
+				s = tt.start
+			}
+			got, err := tt.options.Build(s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Build() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 23, 10, 0, 0, 0, berlin)
+	tests := []struct {
+		name     string
+		rule     string
+		holidays map[string]bool
+		want     []string
+	}{
+		{
+			name: "Keeps the time across daylight saving changes",
+			rule: "FREQ=WEEKLY;COUNT=3",
+			want: []string{"2026-03-23T10:00:00+01:00", "2026-03-30T10:00:00+02:00", "2026-04-06T10:00:00+02:00"},
+		},
+		{
+			name:     "Holidays count towards the count",
+			rule:     "FREQ=WEEKLY;COUNT=3",
+			holidays: map[string]bool{"2026-03-30": true},
+			want:     []string{"2026-03-23T10:00:00+01:00", "2026-04-06T10:00:00+02:00"},
+		},
+		{
+			name: "Until includes its day",
+			rule: "FREQ=DAILY;UNTIL=20260325",
+			want: []string{"2026-03-23T10:00:00+01:00", "2026-03-24T10:00:00+01:00", "2026-03-25T10:00:00+01:00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Occurrences(tt.rule, start, tt.holidays)
+			if err != nil {
+				t.Fatalf("Occurrences() error = %v", err)
+			}
+			var gotTimes []string
+			for _, o := range got {
+				gotTimes = append(gotTimes, o.Format(time.RFC3339))
+			}
+			if diff := cmp.Diff(tt.want, gotTimes); diff != "" {
+				t.Errorf("Occurrences() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadHolidays(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name:    "Dates with names and comments",
+			content: "# holidays\n2026-04-03 Good Friday\n\n  2026-04-06\n",
+			want:    map[string]bool{"2026-04-03": true, "2026-04-06": true},
+		},
+		{name: "Invalid date", content: "2026-04-03\n03.04.2026 Good Friday\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "holidays.txt")
+			if err := os.WriteFile(fileName, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadHolidays(fileName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadHolidays() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); !tt.wantErr && diff != "" {
+				t.Errorf("ReadHolidays() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	got, err := ReadHolidays("")
+	if err != nil || len(got) != 0 {
+		t.Errorf("ReadHolidays(\"\") = %v, %v, want no holidays", got, err)
+	}
+}
@@ -0,0 +1,203 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency defines the length of the period a rule repeats in.
+type Frequency string
+
+const (
+	// Daily repeats every day.
+	Daily Frequency = "DAILY"
+
+	// Weekly repeats every week.
+	Weekly Frequency = "WEEKLY"
+
+	// Monthly repeats every month.
+	Monthly Frequency = "MONTHLY"
+
+	// Yearly repeats every year.
+	Yearly Frequency = "YEARLY"
+)
+
+// WeekdayNum is a weekday with an optional ordinal, e.g. the second Tuesday (2, Tuesday) or the last Friday
+// (-1, Friday) of a month. N is 0 for every such weekday of the period.
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule is a recurrence rule following RFC 5545 (RRULE). Supported are the frequencies DAILY to YEARLY with
+// INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH, BYSETPOS and WKST.
+type Rule struct {
+	// Freq is the length of the period the rule repeats in.
+	Freq Frequency
+
+	// Interval is the number of periods between two repetitions, 1 if not set.
+	Interval int
+
+	// Count limits the number of occurrences, 0 for no limit.
+	Count int
+
+	// Until is the last possible occurrence, zero for no limit.
+	Until time.Time
+
+	// ByDay limits the occurrences to the weekdays, ordinals count inside the month (or year for yearly rules
+	// without ByMonth).
+	ByDay []WeekdayNum
+
+	// ByMonthDay limits the occurrences to the days of the month, negative values count from the end of the month.
+	ByMonthDay []int
+
+	// ByMonth limits the occurrences to the months.
+	ByMonth []time.Month
+
+	// BySetPos selects occurrences by position inside each period, negative values count from the end.
+	BySetPos []int
+
+	// WeekStart is the first day of the week, relevant for weekly rules with an interval.
+	WeekStart time.Weekday
+
+	// untilLocal is set if Until was given without timezone and has to be read in the timezone of the start.
+	untilLocal bool
+}
+
+// maxPeriods limits the number of periods evaluated, so rules that never match terminate.
+const maxPeriods = 100000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Parse reads a recurrence rule like FREQ=MONTHLY;BYDAY=2TU. A leading RRULE: is ignored.
+func Parse(value string) (Rule, error) {
+	r := Rule{Interval: 1, WeekStart: time.Monday}
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return r, fmt.Errorf("invalid rule part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = Frequency(strings.ToUpper(val))
+			switch r.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				err = fmt.Errorf("unsupported frequency %q", val)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(val)
+			if err == nil && r.Interval < 1 {
+				err = errors.New("interval must be positive")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			r.Until, r.untilLocal, err = parseUntil(val)
+		case "BYDAY":
+			for _, v := range strings.Split(val, ",") {
+				var wd WeekdayNum
+				wd, err = parseWeekdayNum(v)
+				if err != nil {
+					break
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseInts(val, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseInts(val, 12)
+			for _, m := range months {
+				if m < 1 {
+					err = fmt.Errorf("invalid month %d", m)
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "BYSETPOS":
+			r.BySetPos, err = parseInts(val, 366)
+		case "WKST":
+			day, ok := weekdays[strings.ToUpper(val)]
+			if !ok {
+				err = fmt.Errorf("invalid weekday %q", val)
+			}
+			r.WeekStart = day
+		default:
+			err = fmt.Errorf("unsupported rule part %q", key)
+		}
+		if err != nil {
+			return r, fmt.Errorf("invalid rule %q: %w", value, err)
+		}
+	}
+	if r.Freq == "" {
+		return r, fmt.Errorf("invalid rule %q: FREQ is required", value)
+	}
+	return r, nil
+}
+
+// parseUntil reads an UNTIL value. Dates and times without Z are read in the timezone of the start later on.
+func parseUntil(value string) (time.Time, bool, error) {
+	switch {
+	case len(value) == 8:
+		t, err := time.Parse("20060102", value)
+		return t.Add(24*time.Hour - time.Second), true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err := time.Parse("20060102T150405", value)
+	return t, true, err
+}
+
+// parseWeekdayNum reads a weekday like MO, 2TU or -1FR.
+func parseWeekdayNum(value string) (WeekdayNum, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday %q", value)
+	}
+	day, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday %q", value)
+	}
+	wd := WeekdayNum{Day: day}
+	if n := value[:len(value)-2]; n != "" {
+		var err error
+		wd.N, err = strconv.Atoi(n)
+		if err != nil || wd.N == 0 || wd.N > 53 || wd.N < -53 {
+			return WeekdayNum{}, fmt.Errorf("invalid weekday %q", value)
+		}
+	}
+	return wd, nil
+}
+
+// parseInts reads a comma separated list of non-zero numbers within -limit and limit.
+func parseInts(value string, limit int) ([]int, error) {
+	var result []int
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 || n > limit || n < -limit {
+			return nil, fmt.Errorf("value %d out of range", n)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Rule
+		wantErr bool
+	}{
+		{
+			value: "FREQ=WEEKLY;BYDAY=MO,WE",
+			want:  Rule{Freq: Weekly, Interval: 1, WeekStart: time.Monday, ByDay: []WeekdayNum{{Day: time.Monday}, {Day: time.Wednesday}}},
+		},
+		{
+			value: "RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=2TU,-1FR;COUNT=5",
+			want:  Rule{Freq: Monthly, Interval: 2, Count: 5, WeekStart: time.Monday, ByDay: []WeekdayNum{{N: 2, Day: time.Tuesday}, {N: -1, Day: time.Friday}}},
+		},
+		{
+			value: "FREQ=YEARLY;BYMONTH=3,9;BYMONTHDAY=-1;WKST=SU;UNTIL=20301231T000000Z",
+			want:  Rule{Freq: Yearly, Interval: 1, WeekStart: time.Sunday, ByMonth: []time.Month{time.March, time.September}, ByMonthDay: []int{-1}, Until: time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC)},
+		},
+		{value: "BYDAY=MO", wantErr: true},
+		{value: "FREQ=HOURLY", wantErr: true},
+		{value: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := Parse(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(Rule{})); diff != "" {
+				t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+	date := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		from  time.Time
+		to    time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "Weekly keeps time of day across DST change",
+			rule:  "FREQ=WEEKLY",
+			start: date(2026, 10, 19, 10),
+			from:  date(2026, 10, 1, 0),
+			to:    date(2026, 11, 3, 0),
+			want:  []time.Time{date(2026, 10, 19, 10), date(2026, 10, 26, 10), date(2026, 11, 2, 10)},
+		},
+		{
+			name:  "Weekly on several days every other week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start: date(2026, 10, 22, 9),
+			from:  date(2026, 10, 1, 0),
+			to:    date(2026, 11, 10, 0),
+			want:  []time.Time{date(2026, 10, 22, 9), date(2026, 11, 2, 9), date(2026, 11, 5, 9)},
+		},
+		{
+			name:  "Monthly on the second Tuesday",
+			rule:  "FREQ=MONTHLY;BYDAY=2TU",
+			start: date(2026, 10, 13, 18),
+			from:  date(2026, 10, 1, 0),
+			to:    date(2027, 1, 1, 0),
+			want:  []time.Time{date(2026, 10, 13, 18), date(2026, 11, 10, 18), date(2026, 12, 8, 18)},
+		},
+		{
+			name:  "Monthly on the last workday",
+			rule:  "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			start: date(2026, 10, 30, 16),
+			from:  date(2026, 10, 1, 0),
+			to:    date(2027, 1, 1, 0),
+			want:  []time.Time{date(2026, 10, 30, 16), date(2026, 11, 30, 16), date(2026, 12, 31, 16)},
+		},
+		{
+			name:  "Monthly on the 31st skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: date(2026, 10, 31, 8),
+			from:  date(2026, 10, 1, 0),
+			to:    date(2027, 2, 1, 0),
+			want:  []time.Time{date(2026, 10, 31, 8), date(2026, 12, 31, 8), date(2027, 1, 31, 8)},
+		},
+		{
+			name:  "Count includes occurrences before the window",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: date(2026, 10, 19, 7),
+			from:  date(2026, 10, 20, 0),
+			to:    date(2026, 11, 1, 0),
+			want:  []time.Time{date(2026, 10, 20, 7), date(2026, 10, 21, 7)},
+		},
+		{
+			name:  "Until date is inclusive",
+			rule:  "FREQ=DAILY;UNTIL=20261021",
+			start: date(2026, 10, 19, 7),
+			from:  date(2026, 10, 1, 0),
+			to:    date(2026, 11, 1, 0),
+			want:  []time.Time{date(2026, 10, 19, 7), date(2026, 10, 20, 7), date(2026, 10, 21, 7)},
+		},
+		{
+			name:  "Yearly in several months",
+			rule:  "FREQ=YEARLY;BYMONTH=3,9;BYMONTHDAY=1",
+			start: date(2026, 3, 1, 12),
+			from:  date(2026, 1, 1, 0),
+			to:    date(2027, 6, 1, 0),
+			want:  []time.Time{date(2026, 3, 1, 12), date(2026, 9, 1, 12), date(2027, 3, 1, 12)},
+		},
+		{
+			name:  "Rule that never matches terminates",
+			rule:  "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			start: date(2026, 2, 1, 12),
+			from:  date(2026, 1, 1, 0),
+			to:    date(2030, 1, 1, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got := r.Between(tt.start, tt.from, tt.to)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Between() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
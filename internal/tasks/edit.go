@@ -0,0 +1,80 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNoTask is returned when a line to edit is not a checklist item.
+var ErrNoTask = errors.New("line is not a task")
+
+// DetectFormat guesses the format a task line is written in. Lines containing dataview inline fields are
+// reported as FormatDataview, all others as the given fallback.
+func DetectFormat(line string, fallback Format) Format {
+	for _, df := range []dateField{createdField, startField, scheduledField, dueField, cancelledField, doneField} {
+		if strings.Contains(line, "["+df.dataview+"::") {
+			return FormatDataview
+		}
+	}
+	if strings.Contains(line, "[priority::") {
+		return FormatDataview
+	}
+	return fallback
+}
+
+// SetStatus changes the status of the task in line and records the date of the change the way the Tasks plugin
+// does, e.g. "[x] call Bob ✅ 2026-10-17". Only an open task may be completed or cancelled. Apart from the status
+// character and the appended date the line is returned unchanged.
+func SetStatus(line string, status rune, date time.Time, format Format) (string, error) {
+	loc := taskLine.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return "", ErrNoTask
+	}
+	current := line[loc[4]:loc[5]]
+	if current != string(StatusOpen) {
+		return "", fmt.Errorf("task is not open but %q", current)
+	}
+	var field dateField
+	switch status {
+	case StatusDone:
+		field = doneField
+	case StatusCancelled:
+		field = cancelledField
+	default:
+		return "", fmt.Errorf("unsupported status %q", status)
+	}
+	line = line[:loc[4]] + string(status) + line[loc[5]:]
+	return appendField(line, field.render(format, date)), nil
+}
+
+// SetDue changes the due date of the task in line. An existing due date is replaced in place, otherwise the due
+// date is appended. Apart from the date the line is returned unchanged.
+func SetDue(line string, date time.Time, format Format) (string, error) {
+	if !taskLine.MatchString(line) {
+		return "", ErrNoTask
+	}
+	loc := dueField.pattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return appendField(line, dueField.render(format, date)), nil
+	}
+	start, end := loc[2], loc[3]
+	if start < 0 {
+		start, end = loc[4], loc[5]
+	}
+	return line[:start] + date.Format(time.DateOnly) + line[end:], nil
+}
+
+// appendField adds a metadata field to the end of a task line, in front of a trailing block reference and
+// without touching a trailing carriage return.
+func appendField(line, field string) string {
+	suffix := ""
+	if strings.HasSuffix(line, "\r") {
+		line, suffix = strings.TrimSuffix(line, "\r"), "\r"
+	}
+	if loc := blockReference.FindStringIndex(line); loc != nil {
+		return line[:loc[0]] + " " + field + line[loc[0]:] + suffix
+	}
+	return line + " " + field + suffix
+}
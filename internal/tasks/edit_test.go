@@ -0,0 +1,119 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetStatus(t *testing.T) {
+	date := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		line    string
+		status  rune
+		format  Format
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "Complete emoji task",
+			line:   "- [ ] call Bob #task/work 📅 2026-10-20",
+			status: StatusDone,
+			format: FormatEmoji,
+			want:   "- [x] call Bob #task/work 📅 2026-10-20 ✅ 2026-10-17",
+		},
+		{
+			name:   "Cancel dataview task",
+			line:   "  - [ ] call Bob [due:: 2026-10-20]",
+			status: StatusCancelled,
+			format: FormatDataview,
+			want:   "  - [-] call Bob [due:: 2026-10-20] [cancelled:: 2026-10-17]",
+		},
+		{
+			name:   "Keep block reference and carriage return",
+			line:   "- [ ] call Bob ^abc\r",
+			status: StatusDone,
+			format: FormatEmoji,
+			want:   "- [x] call Bob ✅ 2026-10-17 ^abc\r",
+		},
+		{
+			name:    "Already done",
+			line:    "- [x] call Bob ✅ 2026-10-16",
+			status:  StatusDone,
+			format:  FormatEmoji,
+			wantErr: true,
+		},
+		{
+			name:    "No task",
+			line:    "- call Bob",
+			status:  StatusDone,
+			format:  FormatEmoji,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetStatus(tt.line, tt.status, date, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetStatus() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SetStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetDue(t *testing.T) {
+	date := time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		line   string
+		format Format
+		want   string
+	}{
+		{
+			name:   "Replace emoji due date",
+			line:   "- [ ] call Bob 📅 2026-10-20 #task/work",
+			format: FormatEmoji,
+			want:   "- [ ] call Bob 📅 2026-10-24 #task/work",
+		},
+		{
+			name:   "Replace dataview due date",
+			line:   "- [ ] call Bob [due:: 2026-10-20]",
+			format: FormatDataview,
+			want:   "- [ ] call Bob [due:: 2026-10-24]",
+		},
+		{
+			name:   "Append due date",
+			line:   "- [ ] call Bob",
+			format: FormatEmoji,
+			want:   "- [ ] call Bob 📅 2026-10-24",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetDue(tt.line, date, tt.format)
+			if err != nil {
+				t.Fatalf("SetDue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SetDue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	if got := DetectFormat("- [ ] call Bob [due:: 2026-10-20]", FormatEmoji); got != FormatDataview {
+		t.Errorf("DetectFormat() = %q, want %q", got, FormatDataview)
+	}
+	if got := DetectFormat("- [ ] call Bob 📅 2026-10-20", FormatDataview); got != FormatDataview {
+		t.Errorf("DetectFormat() = %q, want fallback %q", got, FormatDataview)
+	}
+}
@@ -0,0 +1,90 @@
+package tasks
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// taskLine matches a checklist item and captures the list prefix, the status and the remaining text.
+	taskLine = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)]) \[)(.)\] ?(.*)$`)
+
+	// tagPattern matches tags inside a task description.
+	tagPattern = regexp.MustCompile(`(?:^|\s)(#[^\s#\[\]]+)`)
+
+	// blockReference matches a trailing block reference like ^abc123, which has to stay at the end of a line.
+	blockReference = regexp.MustCompile(`\s\^[A-Za-z0-9-]+\s*$`)
+
+	// priorityPattern matches a priority in emoji or dataview format.
+	priorityPattern = regexp.MustCompile(`\s*(?:(🔺|⏫|🔼|🔽|⏬)️?|\[priority::\s*(\w+)\])`)
+)
+
+// Parse reads a task from a line of a note. The second return value is false if the line is not a checklist item.
+// Metadata is removed from the description, tags are kept in the description and also collected in Tags.
+func Parse(line string) (Task, bool) {
+	m := taskLine.FindStringSubmatch(strings.TrimRight(line, "\r"))
+	if m == nil {
+		return Task{}, false
+	}
+	text := blockReference.ReplaceAllString(m[3], "")
+	t := Task{Status: []rune(m[2])[0]}
+	if pm := priorityPattern.FindStringSubmatch(text); pm != nil {
+		if pm[2] != "" {
+			t.Priority = Priority(strings.ToLower(pm[2]))
+		} else {
+			for p, e := range priorityEmojis {
+				if e == pm[1] {
+					t.Priority = p
+				}
+			}
+		}
+		text = priorityPattern.ReplaceAllString(text, "")
+	}
+	for _, d := range []struct {
+		field dateField
+		value *time.Time
+	}{
+		{createdField, &t.Created},
+		{startField, &t.Start},
+		{scheduledField, &t.Scheduled},
+		{dueField, &t.Due},
+		{cancelledField, &t.Cancelled},
+		{doneField, &t.Done},
+	} {
+		p := d.field.pattern
+		dm := p.FindStringSubmatch(text)
+		if dm == nil {
+			continue
+		}
+		value := dm[1]
+		if value == "" {
+			value = dm[2]
+		}
+		if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+			*d.value = parsed
+		}
+		text = p.ReplaceAllString(text, "")
+	}
+	for _, tm := range tagPattern.FindAllStringSubmatch(text, -1) {
+		t.Tags = append(t.Tags, tm[1])
+	}
+	t.Description = strings.TrimSpace(text)
+	return t, true
+}
+
+// IsOpen reports whether the task still has to be done.
+func (t Task) IsOpen() bool {
+	return t.Status == StatusOpen
+}
+
+// HasTag reports whether the task carries the given tag. Nested tags match their parents, so a task tagged with
+// #task/work/urgent has the tag #task/work.
+func (t Task) HasTag(tag string) bool {
+	for _, tt := range t.Tags {
+		if strings.EqualFold(tt, tag) || strings.HasPrefix(strings.ToLower(tt), strings.ToLower(tag)+"/") {
+			return true
+		}
+	}
+	return false
+}
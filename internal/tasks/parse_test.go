@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		line     string
+		wantOK   bool
+		wantDesc string
+		wantTags []string
+		wantPrio Priority
+		wantDue  time.Time
+		wantOpen bool
+	}{
+		{
+			name:     "Emoji task",
+			line:     "- [ ] call Bob #task/work ⏫ 📅 2026-10-20",
+			wantOK:   true,
+			wantDesc: "call Bob #task/work",
+			wantTags: []string{"#task/work"},
+			wantPrio: PriorityHigh,
+			wantDue:  due,
+			wantOpen: true,
+		},
+		{
+			name:     "Dataview task with block reference",
+			line:     "  * [x] call Bob [priority:: low] [due:: 2026-10-20] ^abc",
+			wantOK:   true,
+			wantDesc: "call Bob",
+			wantPrio: PriorityLow,
+			wantDue:  due,
+		},
+		{
+			name:   "Plain bullet",
+			line:   "- (10:00) went for a walk",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("Parse() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.Description != tt.wantDesc {
+				t.Errorf("Parse() description = %q, want %q", got.Description, tt.wantDesc)
+			}
+			if len(got.Tags) != len(tt.wantTags) {
+				t.Errorf("Parse() tags = %v, want %v", got.Tags, tt.wantTags)
+			}
+			if got.Priority != tt.wantPrio {
+				t.Errorf("Parse() priority = %q, want %q", got.Priority, tt.wantPrio)
+			}
+			if !got.Due.Equal(tt.wantDue) {
+				t.Errorf("Parse() due = %v, want %v", got.Due, tt.wantDue)
+			}
+			if got.IsOpen() != tt.wantOpen {
+				t.Errorf("Parse() open = %v, want %v", got.IsOpen(), tt.wantOpen)
+			}
+		})
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	task := Task{Tags: []string{"#task/work/urgent"}}
+	if !task.HasTag("#task/work") {
+		t.Errorf("HasTag() = false for parent tag")
+	}
+	if task.HasTag("#task/wo") {
+		t.Errorf("HasTag() = true for tag prefix")
+	}
+}
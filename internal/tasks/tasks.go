@@ -0,0 +1,202 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Format defines the syntax used to render task metadata like dates and priorities.
+type Format string
+
+const (
+	// FormatEmoji renders metadata using the emoji signifiers of the Obsidian Tasks plugin.
+	FormatEmoji Format = "emoji"
+
+	// FormatDataview renders metadata using dataview inline fields like [due:: 2006-01-02].
+	FormatDataview Format = "dataview"
+)
+
+// Priority represents the priority of a task as understood by the Obsidian Tasks plugin.
+type Priority string
+
+const (
+	PriorityNone    Priority = ""
+	PriorityLowest  Priority = "lowest"
+	PriorityLow     Priority = "low"
+	PriorityMedium  Priority = "medium"
+	PriorityHigh    Priority = "high"
+	PriorityHighest Priority = "highest"
+)
+
+// priorityEmojis maps priorities to the emoji signifiers used by the Tasks plugin.
+var priorityEmojis = map[Priority]string{
+	PriorityLowest:  "⏬",
+	PriorityLow:     "🔽",
+	PriorityMedium:  "🔼",
+	PriorityHigh:    "⏫",
+	PriorityHighest: "🔺",
+}
+
+const (
+	// StatusOpen marks a task that still has to be done.
+	StatusOpen = ' '
+
+	// StatusDone marks a completed task.
+	StatusDone = 'x'
+
+	// StatusCancelled marks a cancelled task.
+	StatusCancelled = '-'
+)
+
+// dateField describes a date property of a task in both supported formats.
+type dateField struct {
+	emoji    string
+	dataview string
+
+	// pattern matches the field in both formats, the date is captured in the first or second group.
+	pattern *regexp.Regexp
+}
+
+var (
+	createdField   = newDateField("➕", "created")
+	startField     = newDateField("🛫", "start")
+	scheduledField = newDateField("⏳", "scheduled")
+	dueField       = newDateField("📅", "due")
+	cancelledField = newDateField("❌", "cancelled")
+	doneField      = newDateField("✅", "completion")
+)
+
+// newDateField creates a dateField and compiles the expression to find it in a task line.
+func newDateField(emoji, dataview string) dateField {
+	return dateField{
+		emoji:    emoji,
+		dataview: dataview,
+		pattern:  regexp.MustCompile(fmt.Sprintf(`\s*(?:%s️?\s*(\d{4}-\d{2}-\d{2})|\[%s::\s*(\d{4}-\d{2}-\d{2})\])`, regexp.QuoteMeta(emoji), dataview)),
+	}
+}
+
+// Task represents a single checklist item in the format of the Obsidian Tasks plugin.
+type Task struct {
+	// Status is the character between the brackets, see StatusOpen, StatusDone and StatusCancelled.
+	Status rune
+
+	// Description is the free text of the task.
+	Description string
+
+	// Tags contains the tags of the task including the leading '#'.
+	Tags []string
+
+	// Priority is the priority of the task, PriorityNone if not set.
+	Priority Priority
+
+	// Created is the date the task was created, zero if not set.
+	Created time.Time
+
+	// Start is the date work on the task may start, zero if not set.
+	Start time.Time
+
+	// Scheduled is the date the task is planned for, zero if not set.
+	Scheduled time.Time
+
+	// Due is the date the task must be done, zero if not set.
+	Due time.Time
+
+	// Cancelled is the date the task was cancelled, zero if not set.
+	Cancelled time.Time
+
+	// Done is the date the task was completed, zero if not set.
+	Done time.Time
+}
+
+// ParseFormat validates the given string and returns the matching Format.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case FormatEmoji:
+		return FormatEmoji, nil
+	case FormatDataview:
+		return FormatDataview, nil
+	}
+	return "", fmt.Errorf("unknown task format %q (emoji/dataview)", s)
+}
+
+// ParsePriority validates the given string and returns the matching Priority.
+func ParsePriority(s string) (Priority, error) {
+	p := Priority(strings.ToLower(strings.TrimSpace(s)))
+	if p == PriorityNone {
+		return PriorityNone, nil
+	}
+	if _, ok := priorityEmojis[p]; !ok {
+		return PriorityNone, fmt.Errorf("unknown priority %q (lowest/low/medium/high/highest)", s)
+	}
+	return p, nil
+}
+
+// NormalizeTag turns a tag into the form used in notes. Tags starting with '#' are used verbatim,
+// all others are prefixed with '#' and the given prefix, so "work" with prefix "task/" becomes "#task/work".
+func NormalizeTag(tag, prefix string) string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return ""
+	}
+	if strings.HasPrefix(tag, "#") {
+		return tag
+	}
+	return "#" + prefix + tag
+}
+
+// Render returns the task as a checklist item without the list marker, e.g.
+// "[ ] call Bob #task/work ⏫ 📅 2026-10-20". Metadata is written in the order the Tasks plugin uses.
+func (t Task) Render(format Format) (string, error) {
+	if strings.TrimSpace(t.Description) == "" {
+		return "", errors.New("task description cannot be empty")
+	}
+	status := t.Status
+	if status == 0 {
+		status = StatusOpen
+	}
+	parts := []string{fmt.Sprintf("[%c]", status), strings.TrimSpace(t.Description)}
+	for _, tag := range t.Tags {
+		if tag != "" && !strings.Contains(" "+t.Description+" ", " "+tag+" ") {
+			parts = append(parts, tag)
+		}
+	}
+	if t.Priority != PriorityNone {
+		emoji, ok := priorityEmojis[t.Priority]
+		if !ok {
+			return "", fmt.Errorf("unknown priority %q", t.Priority)
+		}
+		if format == FormatDataview {
+			parts = append(parts, fmt.Sprintf("[priority:: %s]", t.Priority))
+		} else {
+			parts = append(parts, emoji)
+		}
+	}
+	for _, d := range []struct {
+		field dateField
+		value time.Time
+	}{
+		{createdField, t.Created},
+		{startField, t.Start},
+		{scheduledField, t.Scheduled},
+		{dueField, t.Due},
+		{cancelledField, t.Cancelled},
+		{doneField, t.Done},
+	} {
+		if d.value.IsZero() {
+			continue
+		}
+		parts = append(parts, d.field.render(format, d.value))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// render formats a single date field in the given format.
+func (df dateField) render(format Format, value time.Time) string {
+	if format == FormatDataview {
+		return fmt.Sprintf("[%s:: %s]", df.dataview, value.Format(time.DateOnly))
+	}
+	return fmt.Sprintf("%s %s", df.emoji, value.Format(time.DateOnly))
+}
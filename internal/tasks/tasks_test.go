@@ -0,0 +1,102 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	scheduled := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		task    Task
+		format  Format
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "Plain task",
+			task:   Task{Description: "call Bob"},
+			format: FormatEmoji,
+			want:   "[ ] call Bob",
+		},
+		{
+			name: "Emoji task with all flags",
+			task: Task{
+				Description: "call Bob",
+				Tags:        []string{"#task/work"},
+				Priority:    PriorityHigh,
+				Scheduled:   scheduled,
+				Due:         due,
+			},
+			format: FormatEmoji,
+			want:   "[ ] call Bob #task/work ⏫ ⏳ 2026-10-19 📅 2026-10-20",
+		},
+		{
+			name: "Dataview task with all flags",
+			task: Task{
+				Description: "call Bob",
+				Tags:        []string{"#task/work"},
+				Priority:    PriorityHigh,
+				Scheduled:   scheduled,
+				Due:         due,
+			},
+			format: FormatDataview,
+			want:   "[ ] call Bob #task/work [priority:: high] [scheduled:: 2026-10-19] [due:: 2026-10-20]",
+		},
+		{
+			name:   "Tag already in description",
+			task:   Task{Description: "fix #task/dev build", Tags: []string{"#task/dev"}},
+			format: FormatEmoji,
+			want:   "[ ] fix #task/dev build",
+		},
+		{
+			name:   "Done task",
+			task:   Task{Status: StatusDone, Description: "call Bob", Done: due},
+			format: FormatEmoji,
+			want:   "[x] call Bob ✅ 2026-10-20",
+		},
+		{
+			name:    "Empty description",
+			task:    Task{Description: "  "},
+			format:  FormatEmoji,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.task.Render(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Render() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name   string
+		tag    string
+		prefix string
+		want   string
+	}{
+		{name: "Prefixed", tag: "work", prefix: "task/", want: "#task/work"},
+		{name: "Verbatim", tag: "#project/x", prefix: "task/", want: "#project/x"},
+		{name: "Empty", tag: " ", prefix: "task/", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTag(tt.tag, tt.prefix); got != tt.want {
+				t.Errorf("NormalizeTag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}